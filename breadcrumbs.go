@@ -0,0 +1,101 @@
+package honeybadger
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Breadcrumb records something which happened before an error was reported.
+// The breadcrumbs recorded up to a notice are sent with it.
+type Breadcrumb struct {
+	Message   string         `json:"message"`
+	Category  string         `json:"category"`
+	Metadata  map[string]any `json:"metadata"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// breadcrumbs is a bounded, concurrency-safe trail of breadcrumbs. When full,
+// the oldest breadcrumb is dropped.
+type breadcrumbs struct {
+	sync.Mutex
+	trail []*Breadcrumb
+}
+
+func newBreadcrumbs() *breadcrumbs {
+	return &breadcrumbs{}
+}
+
+func (b *breadcrumbs) add(crumb *Breadcrumb, limit int) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.trail = append(b.trail, crumb)
+	if limit > 0 && len(b.trail) > limit {
+		b.trail = slices.Delete(b.trail, 0, len(b.trail)-limit)
+	}
+}
+
+func (b *breadcrumbs) all() []*Breadcrumb {
+	b.Lock()
+	defer b.Unlock()
+	return slices.Clone(b.trail)
+}
+
+func (b *breadcrumbs) clear() {
+	b.Lock()
+	b.trail = nil
+	b.Unlock()
+}
+
+func newBreadcrumb(message, category string, metadata map[string]any) *Breadcrumb {
+	if category == "" {
+		category = "custom"
+	}
+	return &Breadcrumb{
+		Message:   message,
+		Category:  category,
+		Metadata:  maps.Clone(metadata),
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// breadcrumbTrail is passed to newNotice to attach breadcrumbs.
+type breadcrumbTrail []*Breadcrumb
+
+type breadcrumbsKey struct{}
+
+// WithBreadcrumbs returns a copy of ctx with its own breadcrumb trail.
+// Breadcrumbs added with AddBreadcrumbContext are recorded there instead of
+// on the client, and are sent with notices reported for ctx.
+func WithBreadcrumbs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, breadcrumbsKey{}, newBreadcrumbs())
+}
+
+func breadcrumbsFromContext(ctx context.Context) *breadcrumbs {
+	if ctx == nil {
+		return nil
+	}
+	b, _ := ctx.Value(breadcrumbsKey{}).(*breadcrumbs)
+	return b
+}
+
+// mergeBreadcrumbs combines trails in chronological order, keeping the most
+// recent limit breadcrumbs.
+func mergeBreadcrumbs(limit int, trails ...[]*Breadcrumb) []*Breadcrumb {
+	var merged []*Breadcrumb
+	for _, trail := range trails {
+		merged = append(merged, trail...)
+	}
+
+	slices.SortStableFunc(merged, func(a, b *Breadcrumb) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	if limit > 0 && len(merged) > limit {
+		merged = merged[len(merged)-limit:]
+	}
+	return merged
+}
@@ -0,0 +1,109 @@
+package honeybadger
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBreadcrumbsLimit(t *testing.T) {
+	trail := newBreadcrumbs()
+	for _, message := range []string{"one", "two", "three"} {
+		trail.add(newBreadcrumb(message, "", nil), 2)
+	}
+
+	crumbs := trail.all()
+	if len(crumbs) != 2 || crumbs[0].Message != "two" || crumbs[1].Message != "three" {
+		t.Errorf("Expected oldest breadcrumb to be dropped. actual=%#v", crumbs)
+	}
+	if crumbs[0].Category != "custom" {
+		t.Errorf("Expected default category. expected=%#v actual=%#v", "custom", crumbs[0].Category)
+	}
+
+	trail.clear()
+	if crumbs := trail.all(); len(crumbs) != 0 {
+		t.Errorf("Expected empty trail after clear. actual=%#v", crumbs)
+	}
+}
+
+func TestBreadcrumbsConcurrentAdd(t *testing.T) {
+	var wg sync.WaitGroup
+	trail := newBreadcrumbs()
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				trail.add(newBreadcrumb("crumb", "", nil), 50)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if crumbs := trail.all(); len(crumbs) != 50 {
+		t.Errorf("Expected trail to be bounded. expected=%d actual=%d", 50, len(crumbs))
+	}
+}
+
+func TestMergeBreadcrumbs(t *testing.T) {
+	now := time.Now()
+	a := []*Breadcrumb{{Message: "a1", Timestamp: now}, {Message: "a2", Timestamp: now.Add(2 * time.Second)}}
+	b := []*Breadcrumb{{Message: "b1", Timestamp: now.Add(time.Second)}}
+
+	merged := mergeBreadcrumbs(2, a, b)
+	if len(merged) != 2 || merged[0].Message != "b1" || merged[1].Message != "a2" {
+		t.Errorf("Expected most recent breadcrumbs in order. actual=%#v", merged)
+	}
+}
+
+func TestNotifyWithBreadcrumbs(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true})
+
+	metadata := map[string]any{"user_id": 1}
+	client.AddBreadcrumb("signed in", "auth", metadata)
+	metadata["user_id"] = 2
+
+	ctx := WithBreadcrumbs(context.Background())
+	client.AddBreadcrumbContext(ctx, "loaded cart", "", nil)
+
+	client.Notify("Cobras!")
+	client.Notify("Cobras!", ctx)
+
+	notices := backend.GetNotices()
+	if len(notices) != 2 {
+		t.Fatalf("Expected 2 notices. actual=%d", len(notices))
+	}
+
+	if crumbs := notices[0].Breadcrumbs; len(crumbs) != 1 || crumbs[0].Metadata["user_id"] != 1 {
+		t.Errorf("Expected client breadcrumbs only. actual=%#v", crumbs)
+	}
+	if crumbs := notices[1].Breadcrumbs; len(crumbs) != 2 || crumbs[1].Message != "loaded cart" {
+		t.Errorf("Expected client and context breadcrumbs. actual=%#v", crumbs)
+	}
+
+	var payload hash
+	if err := json.Unmarshal(notices[1].toJSON(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	breadcrumbs, _ := payload["breadcrumbs"].(map[string]any)
+	trail, _ := breadcrumbs["trail"].([]any)
+	if breadcrumbs["enabled"] != true || len(trail) != 2 {
+		t.Errorf("Expected breadcrumbs in payload. actual=%#v", payload["breadcrumbs"])
+	}
+}
+
+func TestEventBreadcrumbs(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, EventBreadcrumbs: true})
+
+	client.Event("user_login", map[string]any{"user_id": 1})
+
+	crumbs := client.breadcrumbs.all()
+	if len(crumbs) != 1 || crumbs[0].Message != "user_login" || crumbs[0].Category != "event" {
+		t.Errorf("Expected event breadcrumb. actual=%#v", crumbs)
+	}
+}
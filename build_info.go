@@ -0,0 +1,51 @@
+package honeybadger
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// BuildInfo describes the build of the binary which reported a notice. It is
+// read from runtime/debug.ReadBuildInfo.
+type BuildInfo struct {
+	GoVersion     string `json:"go_version,omitempty"`
+	Module        string `json:"module,omitempty"`
+	ModuleVersion string `json:"module_version,omitempty"`
+	VCSRevision   string `json:"vcs_revision,omitempty"`
+	VCSModified   bool   `json:"vcs_modified,omitempty"`
+}
+
+// readBuildInfo returns the BuildInfo of the binary, which is only read once.
+var readBuildInfo = sync.OnceValue(func() BuildInfo {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return BuildInfo{}
+	}
+	return parseBuildInfo(info)
+})
+
+func parseBuildInfo(info *debug.BuildInfo) BuildInfo {
+	build := BuildInfo{
+		GoVersion:     info.GoVersion,
+		Module:        info.Main.Path,
+		ModuleVersion: info.Main.Version,
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.VCSRevision = setting.Value
+		case "vcs.modified":
+			build.VCSModified = setting.Value == "true"
+		}
+	}
+	return build
+}
+
+// revision returns Configuration.Revision, or the VCS revision the binary was
+// built from.
+func revision(config *Configuration) string {
+	if config.Revision != "" {
+		return config.Revision
+	}
+	return readBuildInfo().VCSRevision
+}
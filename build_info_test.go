@@ -0,0 +1,47 @@
+package honeybadger
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestParseBuildInfo(t *testing.T) {
+	build := parseBuildInfo(&debug.BuildInfo{
+		GoVersion: "go1.24.0",
+		Main:      debug.Module{Path: "example.com/app", Version: "v1.2.3"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "abc123"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	})
+
+	expected := BuildInfo{
+		GoVersion:     "go1.24.0",
+		Module:        "example.com/app",
+		ModuleVersion: "v1.2.3",
+		VCSRevision:   "abc123",
+		VCSModified:   true,
+	}
+	if build != expected {
+		t.Errorf("Unexpected build info. expected=%#v actual=%#v", expected, build)
+	}
+}
+
+func TestNoticeRevision(t *testing.T) {
+	notice := newNotice(newConfig(Configuration{}), newError("Cobras!", 0))
+	if notice.Revision != readBuildInfo().VCSRevision {
+		t.Errorf("Expected build revision. expected=%#v actual=%#v", readBuildInfo().VCSRevision, notice.Revision)
+	}
+	if notice.Build.GoVersion == "" {
+		t.Errorf("Expected Go version in build info. actual=%#v", notice.Build)
+	}
+
+	notice = newNotice(newConfig(Configuration{Revision: "abc123"}), newError("Cobras!", 0))
+	server := (*notice.asJSON())["server"].(*hash)
+	if (*server)["revision"] != "abc123" {
+		t.Errorf("Expected configured revision in server. actual=%#v", (*server)["revision"])
+	}
+	if (*server)["build"] != notice.Build {
+		t.Errorf("Expected build info in server. actual=%#v", (*server)["build"])
+	}
+}
@@ -0,0 +1,55 @@
+package honeybadger
+
+// CheckIns is the feature for reporting check-ins. The ID or slug of the
+// check-in is appended to its endpoint.
+var CheckIns = Feature{"check_in"}
+
+// checkIn is the payload of a check-in reported with Client.CheckIn or
+// Client.CheckInBySlug. Exactly one of id and slug is set.
+type checkIn struct {
+	id   string
+	slug string
+}
+
+func (c *checkIn) toJSON() []byte {
+	return []byte("{}")
+}
+
+// feature returns the check-in endpoint of c. Slugs are scoped to the
+// project of apiKey.
+func (c *checkIn) feature(apiKey string) Feature {
+	if c.slug != "" {
+		return Feature{CheckIns.Endpoint + "/" + apiKey + "/" + c.slug}
+	}
+	return Feature{CheckIns.Endpoint + "/" + c.id}
+}
+
+// CheckIn reports the check-in id to Honeybadger.
+func (client *Client) CheckIn(id string) error {
+	return client.sendCheckIn(&checkIn{id: id})
+}
+
+// CheckInBySlug reports the check-in with the slug configured in the project
+// of Configuration.APIKey to Honeybadger.
+func (client *Client) CheckInBySlug(slug string) error {
+	return client.sendCheckIn(&checkIn{slug: slug})
+}
+
+func (client *Client) sendCheckIn(c *checkIn) error {
+	if err := client.Config.Backend.Notify(c.feature(client.Config.APIKey), c); err != nil {
+		client.Config.Logger.Printf("check-in error: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// RunCheckIn runs job and reports the check-in id when it returns a nil
+// error. Otherwise the error is reported as a notice with the check-in id in
+// its context, and returned.
+func (client *Client) RunCheckIn(id string, job func() error) error {
+	if err := job(); err != nil {
+		client.Notify(err, Context{"check_in_id": id})
+		return err
+	}
+	return client.CheckIn(id)
+}
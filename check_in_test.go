@@ -0,0 +1,72 @@
+package honeybadger
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckInEndpoints(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		paths = append(paths, r.URL.EscapedPath())
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	client := New(Configuration{APIKey: "badgers", Endpoint: ts.URL})
+
+	if err := client.CheckIn("1MqIo1"); err != nil {
+		t.Errorf("Unexpected error. err=%v", err)
+	}
+	if err := client.CheckInBySlug("daily backup"); err != nil {
+		t.Errorf("Unexpected error. err=%v", err)
+	}
+
+	expected := []string{"/v1/check_in/1MqIo1", "/v1/check_in/badgers/daily%20backup"}
+	if len(paths) != 2 || paths[0] != expected[0] || paths[1] != expected[1] {
+		t.Errorf("Unexpected check-in paths. expected=%#v actual=%#v", expected, paths)
+	}
+}
+
+func TestCheckInError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(403)
+	}))
+	defer ts.Close()
+
+	client := New(Configuration{APIKey: "badgers", Endpoint: ts.URL})
+
+	if err := client.CheckIn("1MqIo1"); err != ErrUnauthorized {
+		t.Errorf("Expected backend error. expected=%#v actual=%#v", ErrUnauthorized, err)
+	}
+}
+
+func TestRunCheckIn(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true})
+
+	if err := client.RunCheckIn("1MqIo1", func() error { return nil }); err != nil {
+		t.Errorf("Unexpected error. err=%v", err)
+	}
+
+	jobErr := errors.New("backup failed")
+	if err := client.RunCheckIn("1MqIo1", func() error { return jobErr }); err != jobErr {
+		t.Errorf("Expected job error. expected=%#v actual=%#v", jobErr, err)
+	}
+
+	checkIns := backend.GetCheckIns()
+	if len(checkIns) != 1 || checkIns[0].ID != "1MqIo1" {
+		t.Errorf("Expected check-in only for successful job. actual=%#v", checkIns)
+	}
+
+	notices := backend.GetNotices()
+	if len(notices) != 1 {
+		t.Fatalf("Expected notice for failed job. actual=%d", len(notices))
+	}
+	if notices[0].ErrorMessage != "backup failed" || notices[0].Context["check_in_id"] != "1MqIo1" {
+		t.Errorf("Unexpected notice. message=%#v context=%#v", notices[0].ErrorMessage, notices[0].Context)
+	}
+}
@@ -1,8 +1,12 @@
 package honeybadger
 
 import (
+	"context"
 	"errors"
+	"maps"
 	"net/http"
+	"sync/atomic"
+	"time"
 )
 
 // The Payload interface is implemented by any type which can be handled by the
@@ -33,6 +37,36 @@ type Client struct {
 	beforeNotifyHandlers []noticeHandler
 	eventsWorker         *EventsWorker
 	beforeEventHandlers  []eventHandler
+	breadcrumbs          *breadcrumbs
+	user                 *atomic.Pointer[User]
+	dedup                *deduplicator
+	limiter              *rateLimiter
+	stats                *clientStats
+	statsSampler         *statsSampler
+}
+
+// ClientStats counts the notices which were not sent individually by a
+// client.
+type ClientStats struct {
+	// Deduplicated is the number of notices collapsed into an earlier
+	// notice within Configuration.DedupWindow.
+	Deduplicated int64
+	// RateLimited is the number of notices dropped by
+	// Configuration.RateLimit.
+	RateLimited int64
+	// Sampled is the number of notices dropped by
+	// Configuration.SampleRates.
+	Sampled int64
+	// Truncated is the number of notices and events truncated to fit
+	// the payload limits of the Configuration.
+	Truncated int64
+}
+
+type clientStats struct {
+	deduplicated atomic.Int64
+	rateLimited  atomic.Int64
+	sampled      atomic.Int64
+	truncated    atomic.Int64
 }
 
 func eventsConfigChanged(config *Configuration) bool {
@@ -47,6 +81,11 @@ func (client *Client) Configure(config Configuration) {
 		client.eventsWorker.Stop()
 		client.eventsWorker = NewEventsWorker(client.Config)
 	}
+
+	if (config.StatsProvider != nil || config.StatsInterval > 0) && client.statsSampler != nil {
+		client.statsSampler.Stop()
+		client.statsSampler = newStatsSampler(client.Config.StatsProvider, client.Config.StatsInterval)
+	}
 }
 
 // SetContext updates the client context with supplied context.
@@ -59,6 +98,17 @@ func (client *Client) ClearContext() {
 	client.context.Clear()
 }
 
+// SetUser sets the user sent with all notices, and with events when
+// Configuration.EventsIncludeUser is set.
+func (client *Client) SetUser(user User) {
+	client.user.Store(&user)
+}
+
+// ClearUser clears the user set with SetUser.
+func (client *Client) ClearUser() {
+	client.user.Store(nil)
+}
+
 // SetEventContext updates the client event context with supplied context.
 func (client *Client) SetEventContext(context Context) {
 	client.eventContext.Update(context)
@@ -69,17 +119,64 @@ func (client *Client) ClearEventContext() {
 	client.eventContext.Clear()
 }
 
-// Flush blocks until the worker has processed its queue.
+// AddBreadcrumb records a breadcrumb which is sent with subsequent notices.
+// The category defaults to "custom".
+func (client *Client) AddBreadcrumb(message, category string, metadata map[string]any) {
+	client.breadcrumbs.add(newBreadcrumb(message, category, metadata), client.Config.MaxBreadcrumbs)
+}
+
+// AddBreadcrumbContext records a breadcrumb on the trail of ctx created by
+// WithBreadcrumbs, or on the client when ctx has no trail.
+func (client *Client) AddBreadcrumbContext(ctx context.Context, message, category string, metadata map[string]any) {
+	trail := breadcrumbsFromContext(ctx)
+	if trail == nil {
+		trail = client.breadcrumbs
+	}
+	trail.add(newBreadcrumb(message, category, metadata), client.Config.MaxBreadcrumbs)
+}
+
+// ClearBreadcrumbs clears the breadcrumbs recorded on the client.
+func (client *Client) ClearBreadcrumbs() {
+	client.breadcrumbs.clear()
+}
+
+// Stats returns the counters of the client.
+func (client *Client) Stats() ClientStats {
+	return ClientStats{
+		Deduplicated: client.stats.deduplicated.Load(),
+		RateLimited:  client.stats.rateLimited.Load(),
+		Sampled:      client.stats.sampled.Load(),
+		Truncated:    client.stats.truncated.Load(),
+	}
+}
+
+// Flush sends the notices collapsed by deduplication and blocks until the
+// worker has processed its queue.
 func (client *Client) Flush() {
+	client.dedup.flush(client.sendRepeat)
 	client.worker.Flush()
 	if client.eventsWorker != nil {
 		client.eventsWorker.Flush()
 	}
 }
 
+// Stop flushes the client and stops its background workers. The client
+// shouldn't be used after it is stopped.
+func (client *Client) Stop() {
+	client.Flush()
+	if client.eventsWorker != nil {
+		client.eventsWorker.Stop()
+	}
+	if client.statsSampler != nil {
+		client.statsSampler.Stop()
+	}
+}
+
 // BeforeNotify adds a callback function which is run before a notice is
 // reported to Honeybadger. If any function returns an error the notification
-// will be skipped, otherwise it will be sent.
+// will be skipped, otherwise it will be sent. Returning ErrNoticeIgnored
+// ignores the notice, and Notify returns ErrNoticeIgnored rather than a
+// failure.
 func (client *Client) BeforeNotify(handler func(notice *Notice) error) {
 	client.beforeNotifyHandlers = append(client.beforeNotifyHandlers, handler)
 }
@@ -88,16 +185,64 @@ func (client *Client) BeforeEvent(handler func(event map[string]any) error) {
 	client.beforeEventHandlers = append(client.beforeEventHandlers, handler)
 }
 
-// Notify reports the error err to the Honeybadger service.
+// Notify reports the error err to the Honeybadger service. It returns an
+// empty token and ErrNoticeIgnored when the notice is ignored, an empty token
+// and a nil error when it is sampled out, and the token of the notice it was
+// collapsed into when it is deduplicated.
 func (client *Client) Notify(err interface{}, extra ...interface{}) (string, error) {
-	extra = append([]interface{}{client.context.internal}, extra...)
-	notice := newNotice(client.Config, newError(err, 2), extra...)
+	client.context.RLock()
+	globalContext := maps.Clone(client.context.internal)
+	client.context.RUnlock()
+
+	global := []interface{}{globalContext, breadcrumbTrail(client.breadcrumbs.all())}
+	if user := client.user.Load(); user != nil {
+		global = append(global, *user)
+	}
+	extra = append(global, extra...)
+	e := newError(err, 2)
+	if ignoreError(e, extra, client.Config.IgnoreErrors) {
+		return "", ErrNoticeIgnored
+	}
+	notice := newNotice(client.Config, e, extra...)
+	notice.limits = newPayloadLimits(client.Config, &client.stats.truncated)
+	if notice.Fingerprint == "" && client.Config.Fingerprinter != nil {
+		notice.Fingerprint = client.Config.Fingerprinter(notice)
+	}
 	for _, handler := range client.beforeNotifyHandlers {
-		if err := handler(notice); err != nil {
+		if err := handler(notice); errors.Is(err, ErrNoticeIgnored) {
+			return "", ErrNoticeIgnored
+		} else if err != nil {
 			return "", err
 		}
 	}
 
+	if sampleNotice(notice, client.Config.SampleRates) {
+		client.stats.sampled.Add(1)
+		return "", nil
+	}
+	notice.stats = getStats(client.statsSampler.stats())
+
+	if client.Config.DedupWindow > 0 {
+		if token, ok := client.dedup.add(notice, client.Config.DedupWindow, client.sendRepeat); ok {
+			client.stats.deduplicated.Add(1)
+			return token, nil
+		}
+	}
+
+	if err := client.send(notice); err != nil {
+		return "", err
+	}
+
+	return notice.Token, nil
+}
+
+// send reports notice to the backend, subject to Configuration.RateLimit.
+func (client *Client) send(notice *Notice) error {
+	if !client.limiter.allow(client.Config.RateLimit, client.Config.RateLimitInterval, time.Now()) {
+		client.stats.rateLimited.Add(1)
+		return ErrRateLimited
+	}
+
 	notifyFn := func() error {
 		return client.Config.Backend.Notify(Notices, notice)
 	}
@@ -105,22 +250,40 @@ func (client *Client) Notify(err interface{}, extra ...interface{}) (string, err
 	if client.Config.Sync {
 		if notifyErr := notifyFn(); notifyErr != nil {
 			client.Config.Logger.Printf("notify error: %v\n", notifyErr)
-			return "", notifyErr
+			return notifyErr
 		}
 	} else {
 		if workerPushErr := client.worker.Push(notifyFn); workerPushErr != nil {
 			client.Config.Logger.Printf("worker error: %v\n", workerPushErr)
-			return "", workerPushErr
+			return workerPushErr
 		}
 	}
 
-	return notice.Token, nil
+	return nil
+}
+
+// sendRepeat sends the repeats collapsed by deduplication. Errors are logged
+// by send.
+func (client *Client) sendRepeat(notice *Notice) {
+	client.send(notice)
+}
+
+// NotifyContext reports the error err to the Honeybadger service, including
+// the Context, Tags, fingerprint and breadcrumbs stored in ctx.
+func (client *Client) NotifyContext(ctx context.Context, err interface{}, extra ...interface{}) (string, error) {
+	return client.Notify(newError(err, 2), append([]interface{}{ctx}, extra...)...)
 }
 
 func (client *Client) Event(eventType string, eventData map[string]any) error {
 	client.eventContext.RLock()
-	event := newEventPayload(eventType, client.eventContext.internal, eventData)
+	eventContext := client.eventContext.internal
+	if user := client.user.Load(); user != nil && client.Config.EventsIncludeUser {
+		eventContext = maps.Clone(eventContext)
+		eventContext.Update(user.Context())
+	}
+	event := newEventPayload(eventType, eventContext, eventData)
 	client.eventContext.RUnlock()
+	event.limits = newPayloadLimits(client.Config, &client.stats.truncated)
 
 	for _, handler := range client.beforeEventHandlers {
 		err := handler(event.data)
@@ -132,6 +295,10 @@ func (client *Client) Event(eventType string, eventData map[string]any) error {
 		}
 	}
 
+	if client.Config.EventBreadcrumbs {
+		client.AddBreadcrumb(eventType, "event", event.data)
+	}
+
 	if client.Config.Sync {
 		return client.Config.Backend.Event([]*eventPayload{event})
 	}
@@ -140,11 +307,19 @@ func (client *Client) Event(eventType string, eventData map[string]any) error {
 	return nil
 }
 
+// panicExtra returns the extra data attached to notices of recovered panics.
+func (client *Client) panicExtra() []interface{} {
+	if !client.Config.GoroutineDump {
+		return nil
+	}
+	return []interface{}{captureGoroutines(client.Config.GoroutineDumpMaxBytes)}
+}
+
 // Monitor automatically reports panics which occur in the function it's called
 // from. Must be deferred.
 func (client *Client) Monitor() {
 	if err := recover(); err != nil {
-		client.Notify(newError(err, 2))
+		client.Notify(newError(err, 2), client.panicExtra()...)
 		client.Flush()
 		panic(err)
 	}
@@ -157,9 +332,12 @@ func (client *Client) Handler(h http.Handler) http.Handler {
 		h = http.DefaultServeMux
 	}
 	fn := func(w http.ResponseWriter, r *http.Request) {
+		if client.Config.CaptureRequestBody {
+			captureRequestBody(r, client.Config.RequestBodyMaxBytes)
+		}
 		defer func() {
 			if err := recover(); err != nil {
-				client.Notify(newError(err, 2), r)
+				client.Notify(newError(err, 2), append(client.panicExtra(), r)...)
 				panic(err)
 			}
 		}()
@@ -180,6 +358,12 @@ func New(c Configuration) *Client {
 		context:      newContextSync(),
 		eventContext: newContextSync(),
 		eventsWorker: eventsWorker,
+		breadcrumbs:  newBreadcrumbs(),
+		user:         &atomic.Pointer[User]{},
+		dedup:        newDeduplicator(),
+		limiter:      &rateLimiter{},
+		stats:        &clientStats{},
+		statsSampler: newStatsSampler(config.StatsProvider, config.StatsInterval),
 	}
 
 	return &client
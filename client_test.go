@@ -2,6 +2,7 @@ package honeybadger
 
 import (
 	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -260,9 +261,15 @@ func mockClient(c Configuration) (Client, *mockWorker, *mockBackend) {
 	backendConfig.update(&c)
 
 	client := Client{
-		Config:  newConfig(*backendConfig),
-		worker:  worker,
-		context: newContextSync(),
+		Config:       newConfig(*backendConfig),
+		worker:       worker,
+		context:      newContextSync(),
+		breadcrumbs:  newBreadcrumbs(),
+		user:         &atomic.Pointer[User]{},
+		dedup:        newDeduplicator(),
+		limiter:      &rateLimiter{},
+		stats:        &clientStats{},
+		statsSampler: newStatsSampler(nil, 0),
 	}
 
 	return client, worker, backend
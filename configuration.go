@@ -3,6 +3,7 @@ package honeybadger
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
 	"strconv"
 	"time"
@@ -32,6 +33,111 @@ type Configuration struct {
 	EventsMaxQueueSize   int
 	EventsMaxRetries     int
 	EventsDropLogInterval time.Duration
+
+	// SourceContextLines is the number of lines of source code included
+	// before and after each backtrace frame. Source is read from files
+	// inside Root; 0 disables it.
+	SourceContextLines int
+
+	// MaxFrames is the maximum number of frames reported in a backtrace.
+	MaxFrames int
+
+	// FrameFilter is called with each backtrace frame before it is
+	// reported. It may modify the frame or return nil to drop it. The
+	// default, DefaultFrameFilter, drops the frames of this package.
+	FrameFilter func(*Frame) *Frame
+
+	// GoroutineDump attaches the stacks of all goroutines to notices of
+	// panics recovered by Monitor and Handler, reading at most
+	// GoroutineDumpMaxBytes of stack output.
+	GoroutineDump         bool
+	GoroutineDumpMaxBytes int
+
+	// MaxBreadcrumbs is the number of breadcrumbs kept and sent with each
+	// notice. When EventBreadcrumbs is set, every event sent with Event is
+	// also recorded as a breadcrumb.
+	MaxBreadcrumbs   int
+	EventBreadcrumbs bool
+
+	// FilterKeys are case-insensitive regular expressions matched against
+	// the keys of params, CGI data, context and URL query strings,
+	// including the keys of nested maps and the JSON names of struct
+	// fields. The values of matching keys are replaced with [FILTERED]
+	// before notices are sent. Defaults to DefaultFilterKeys.
+	FilterKeys []string
+
+	// CaptureRequestBody reports the JSON and form bodies of requests served
+	// by Handler as params. The body is copied as the handler reads it,
+	// keeping at most RequestBodyMaxBytes. Bodies which are truncated or
+	// not read to the end are not reported.
+	CaptureRequestBody  bool
+	RequestBodyMaxBytes int
+
+	// RequestRoute returns the component and action of notices with an
+	// *http.Request. The default, DefaultRequestRoute, uses the pattern
+	// matched by http.ServeMux; routers which store their patterns
+	// elsewhere can be supported with a custom function.
+	RequestRoute func(r *http.Request) (component, action string)
+
+	// EventsIncludeUser merges the user set with SetUser into the data of
+	// every event, like the event context.
+	EventsIncludeUser bool
+
+	// Fingerprinter computes the fingerprint of notices which weren't given
+	// one explicitly, controlling how they are grouped. See
+	// FingerprintByMessage, FingerprintByFrames and FingerprintBySentinel.
+	Fingerprinter func(*Notice) string
+
+	// IgnoreErrors lists the errors which are never reported. Each entry
+	// may be a sentinel error matched with errors.Is, a reflect.Type of an
+	// error type matched with errors.As, a class name, or a
+	// *regexp.Regexp matched against the message. Defaults to
+	// DefaultIgnoreErrors.
+	IgnoreErrors []interface{}
+
+	// DedupWindow collapses notices with the same fingerprint, or the same
+	// class and first application frame, reported within the window of the
+	// first one. The first notice is sent immediately; its repeats are sent
+	// as a single notice when the window ends, with their number in the
+	// "occurrences" context key. 0 disables deduplication.
+	DedupWindow time.Duration
+
+	// RateLimit is the number of notices sent per RateLimitInterval, in
+	// bursts of up to RateLimit notices. Notices over the limit are dropped
+	// and Notify returns ErrRateLimited. 0 disables the limit.
+	RateLimit         int
+	RateLimitInterval time.Duration
+
+	// SampleRates reports only a fraction of the notices matching each
+	// SampleRate, by class, tag or predicate. The first matching SampleRate
+	// applies, and its rate is recorded in the "sample_rate" context key of
+	// the notices reported.
+	SampleRates []SampleRate
+
+	// Revision is the revision of the application reported with notices
+	// and deploys. Defaults to the VCS revision the binary was built from.
+	Revision string
+
+	// StatsProvider provides the system stats reported with notices. The
+	// default reports the memory and CPU limits of the cgroup of the
+	// process; NewNullStatsProvider disables system stats. The stats are
+	// read when the client is created, then refreshed in the background
+	// every StatsInterval until the client is stopped.
+	StatsProvider StatsProvider
+	StatsInterval time.Duration
+
+	// MaxStringLength, MaxCollectionEntries and MaxDepth limit the user
+	// data serialized in notices and events: the context, params, CGI data,
+	// session, cookies and breadcrumb metadata of notices, and the data of
+	// events. Longer strings, in bytes, and maps and slices with more
+	// entries are truncated, and values nested deeper than MaxDepth below
+	// the root of their data are replaced, with markers. Payloads larger
+	// than MaxPayloadBytes have their user data truncated further until
+	// they fit. See ClientStats.Truncated.
+	MaxStringLength      int
+	MaxCollectionEntries int
+	MaxDepth             int
+	MaxPayloadBytes      int
 }
 
 func (c1 *Configuration) update(c2 *Configuration) *Configuration {
@@ -80,6 +186,81 @@ func (c1 *Configuration) update(c2 *Configuration) *Configuration {
 	if c2.EventsDropLogInterval > 0 {
 		c1.EventsDropLogInterval = c2.EventsDropLogInterval
 	}
+	if c2.SourceContextLines > 0 {
+		c1.SourceContextLines = c2.SourceContextLines
+	}
+	if c2.MaxFrames > 0 {
+		c1.MaxFrames = c2.MaxFrames
+	}
+	if c2.FrameFilter != nil {
+		c1.FrameFilter = c2.FrameFilter
+	}
+	if c2.GoroutineDump {
+		c1.GoroutineDump = c2.GoroutineDump
+	}
+	if c2.GoroutineDumpMaxBytes > 0 {
+		c1.GoroutineDumpMaxBytes = c2.GoroutineDumpMaxBytes
+	}
+	if c2.MaxBreadcrumbs > 0 {
+		c1.MaxBreadcrumbs = c2.MaxBreadcrumbs
+	}
+	if c2.EventBreadcrumbs {
+		c1.EventBreadcrumbs = c2.EventBreadcrumbs
+	}
+	if c2.FilterKeys != nil {
+		c1.FilterKeys = c2.FilterKeys
+	}
+	if c2.CaptureRequestBody {
+		c1.CaptureRequestBody = c2.CaptureRequestBody
+	}
+	if c2.RequestBodyMaxBytes > 0 {
+		c1.RequestBodyMaxBytes = c2.RequestBodyMaxBytes
+	}
+	if c2.RequestRoute != nil {
+		c1.RequestRoute = c2.RequestRoute
+	}
+	if c2.EventsIncludeUser {
+		c1.EventsIncludeUser = c2.EventsIncludeUser
+	}
+	if c2.Fingerprinter != nil {
+		c1.Fingerprinter = c2.Fingerprinter
+	}
+	if c2.IgnoreErrors != nil {
+		c1.IgnoreErrors = c2.IgnoreErrors
+	}
+	if c2.DedupWindow > 0 {
+		c1.DedupWindow = c2.DedupWindow
+	}
+	if c2.RateLimit > 0 {
+		c1.RateLimit = c2.RateLimit
+	}
+	if c2.RateLimitInterval > 0 {
+		c1.RateLimitInterval = c2.RateLimitInterval
+	}
+	if c2.SampleRates != nil {
+		c1.SampleRates = c2.SampleRates
+	}
+	if c2.Revision != "" {
+		c1.Revision = c2.Revision
+	}
+	if c2.StatsProvider != nil {
+		c1.StatsProvider = c2.StatsProvider
+	}
+	if c2.StatsInterval > 0 {
+		c1.StatsInterval = c2.StatsInterval
+	}
+	if c2.MaxStringLength > 0 {
+		c1.MaxStringLength = c2.MaxStringLength
+	}
+	if c2.MaxCollectionEntries > 0 {
+		c1.MaxCollectionEntries = c2.MaxCollectionEntries
+	}
+	if c2.MaxDepth > 0 {
+		c1.MaxDepth = c2.MaxDepth
+	}
+	if c2.MaxPayloadBytes > 0 {
+		c1.MaxPayloadBytes = c2.MaxPayloadBytes
+	}
 
 	c1.Sync = c2.Sync
 	return c1
@@ -112,6 +293,29 @@ func newConfig(c Configuration) *Configuration {
 		EventsMaxQueueSize:    GetEnv[int]("HONEYBADGER_EVENTS_MAX_QUEUE_SIZE", 100000),
 		EventsMaxRetries:      GetEnv[int]("HONEYBADGER_EVENTS_MAX_RETRIES", 3),
 		EventsDropLogInterval: GetEnv[time.Duration]("HONEYBADGER_EVENTS_DROP_LOG_INTERVAL", 60*time.Second),
+		SourceContextLines:    GetEnv[int]("HONEYBADGER_SOURCE_CONTEXT_LINES", 0),
+		MaxFrames:             GetEnv[int]("HONEYBADGER_MAX_FRAMES", 20),
+		FrameFilter:           DefaultFrameFilter,
+		GoroutineDump:         GetEnv[bool]("HONEYBADGER_GOROUTINE_DUMP", false),
+		GoroutineDumpMaxBytes: GetEnv[int]("HONEYBADGER_GOROUTINE_DUMP_MAX_BYTES", 256*1024),
+		MaxBreadcrumbs:        GetEnv[int]("HONEYBADGER_MAX_BREADCRUMBS", 40),
+		EventBreadcrumbs:      GetEnv[bool]("HONEYBADGER_EVENT_BREADCRUMBS", false),
+		FilterKeys:            DefaultFilterKeys,
+		CaptureRequestBody:    GetEnv[bool]("HONEYBADGER_CAPTURE_REQUEST_BODY", false),
+		RequestBodyMaxBytes:   GetEnv[int]("HONEYBADGER_REQUEST_BODY_MAX_BYTES", 64*1024),
+		RequestRoute:          DefaultRequestRoute,
+		EventsIncludeUser:     GetEnv[bool]("HONEYBADGER_EVENTS_INCLUDE_USER", false),
+		IgnoreErrors:          DefaultIgnoreErrors,
+		DedupWindow:           GetEnv[time.Duration]("HONEYBADGER_DEDUP_WINDOW", 0),
+		RateLimit:             GetEnv[int]("HONEYBADGER_RATE_LIMIT", 0),
+		RateLimitInterval:     GetEnv[time.Duration]("HONEYBADGER_RATE_LIMIT_INTERVAL", time.Minute),
+		Revision:              GetEnv[string]("HONEYBADGER_REVISION"),
+		StatsProvider:         NewCgroupStatsProvider(DefaultCgroupRoot),
+		StatsInterval:         GetEnv[time.Duration]("HONEYBADGER_STATS_INTERVAL", 30*time.Second),
+		MaxStringLength:       GetEnv[int]("HONEYBADGER_MAX_STRING_LENGTH", 64*1024),
+		MaxCollectionEntries:  GetEnv[int]("HONEYBADGER_MAX_COLLECTION_ENTRIES", 1000),
+		MaxDepth:              GetEnv[int]("HONEYBADGER_MAX_DEPTH", maxPayloadDepth),
+		MaxPayloadBytes:       GetEnv[int]("HONEYBADGER_MAX_PAYLOAD_BYTES", 1024*1024),
 	}
 	config.update(&c)
 
@@ -1,5 +1,10 @@
 package honeybadger
 
+import (
+	"context"
+	"slices"
+)
+
 // Context is used to send extra data to Honeybadger.
 type Context hash
 
@@ -9,3 +14,52 @@ func (context Context) Update(other Context) {
 		context[k] = v
 	}
 }
+
+// noticeScope is the notice data stored in a context.Context. It is never
+// modified once stored; each With function stores an updated copy.
+type noticeScope struct {
+	context     Context
+	tags        Tags
+	fingerprint string
+	user        *User
+}
+
+type noticeScopeKey struct{}
+
+func noticeScopeFromContext(ctx context.Context) noticeScope {
+	if ctx == nil {
+		return noticeScope{}
+	}
+	scope, _ := ctx.Value(noticeScopeKey{}).(noticeScope)
+	return scope
+}
+
+// WithContext returns a copy of ctx carrying c, merged with any Context
+// already stored in ctx. The Context is sent with notices reported with
+// NotifyContext for the returned context, on top of the client-wide context.
+func WithContext(ctx context.Context, c Context) context.Context {
+	scope := noticeScopeFromContext(ctx)
+
+	merged := make(Context, len(scope.context)+len(c))
+	merged.Update(scope.context)
+	merged.Update(c)
+	scope.context = merged
+
+	return context.WithValue(ctx, noticeScopeKey{}, scope)
+}
+
+// WithTags returns a copy of ctx carrying tags in addition to any Tags already
+// stored in ctx.
+func WithTags(ctx context.Context, tags Tags) context.Context {
+	scope := noticeScopeFromContext(ctx)
+	scope.tags = append(slices.Clip(scope.tags), tags...)
+	return context.WithValue(ctx, noticeScopeKey{}, scope)
+}
+
+// WithFingerprint returns a copy of ctx carrying the fingerprint used for
+// notices reported with it.
+func WithFingerprint(ctx context.Context, fingerprint string) context.Context {
+	scope := noticeScopeFromContext(ctx)
+	scope.fingerprint = fingerprint
+	return context.WithValue(ctx, noticeScopeKey{}, scope)
+}
@@ -1,6 +1,11 @@
 package honeybadger
 
-import "testing"
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
 
 func TestContextUpdate(t *testing.T) {
 	c := Context{"foo": "bar"}
@@ -9,3 +14,67 @@ func TestContextUpdate(t *testing.T) {
 		t.Errorf("Context should update values. expected=%#v actual=%#v", "baz", c["foo"])
 	}
 }
+
+func TestWithContext(t *testing.T) {
+	base := WithContext(context.Background(), Context{"foo": "bar", "user_id": 1})
+	ctx := WithContext(base, Context{"user_id": 2})
+
+	if scope := noticeScopeFromContext(ctx); scope.context["foo"] != "bar" || scope.context["user_id"] != 2 {
+		t.Errorf("Expected context to be merged. actual=%#v", scope.context)
+	}
+
+	if scope := noticeScopeFromContext(base); scope.context["user_id"] != 1 {
+		t.Errorf("Expected parent context to be unchanged. actual=%#v", scope.context)
+	}
+}
+
+func TestWithTagsAndFingerprint(t *testing.T) {
+	base := WithTags(context.Background(), Tags{"http"})
+	first := WithTags(base, Tags{"timeout"})
+	second := WithFingerprint(WithTags(base, Tags{"db"}), "Badgers")
+
+	if scope := noticeScopeFromContext(first); !reflect.DeepEqual(scope.tags, Tags{"http", "timeout"}) {
+		t.Errorf("Expected tags to be appended. actual=%#v", scope.tags)
+	}
+
+	scope := noticeScopeFromContext(second)
+	if !reflect.DeepEqual(scope.tags, Tags{"http", "db"}) || scope.fingerprint != "Badgers" {
+		t.Errorf("Unexpected scope. actual=%#v", scope)
+	}
+}
+
+func TestNotifyContext(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true})
+	client.SetContext(Context{"foo": "bar", "user_id": 0})
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			ctx := WithContext(context.Background(), Context{"user_id": id})
+			ctx = WithTags(ctx, Tags{"request"})
+			ctx = WithFingerprint(ctx, "request")
+			client.NotifyContext(ctx, "Cobras!", Context{"request_id": id})
+		}(i)
+	}
+	wg.Wait()
+
+	notices := backend.GetNotices()
+	if len(notices) != 10 {
+		t.Fatalf("Expected 10 notices. actual=%d", len(notices))
+	}
+
+	for _, notice := range notices {
+		if notice.Context["foo"] != "bar" {
+			t.Errorf("Expected client context. actual=%#v", notice.Context)
+		}
+		if notice.Context["user_id"] != notice.Context["request_id"] {
+			t.Errorf("Expected request scoped context. actual=%#v", notice.Context)
+		}
+		if !reflect.DeepEqual(notice.Tags, []string{"request"}) || notice.Fingerprint != "request" {
+			t.Errorf("Expected tags and fingerprint from context. actual=%#v %#v", notice.Tags, notice.Fingerprint)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package honeybadger
+
+import (
+	"sync"
+	"time"
+)
+
+// occurrencesKey is the context key holding the number of repeats collapsed
+// into a deduplicated notice.
+const occurrencesKey = "occurrences"
+
+// deduplicator collapses notices with the same key reported within a window.
+// The first notice of a window is sent as usual; its repeats are collapsed
+// into the first of them, which is sent when the window ends.
+type deduplicator struct {
+	sync.Mutex
+	windows map[string]*dedupWindow
+}
+
+type dedupWindow struct {
+	repeat *Notice
+	count  int
+}
+
+func newDeduplicator() *deduplicator {
+	return &deduplicator{windows: make(map[string]*dedupWindow)}
+}
+
+// dedupKey returns the fingerprint of notice, or its class and first
+// application frame when it has no fingerprint.
+func dedupKey(notice *Notice) string {
+	if notice.Fingerprint != "" {
+		return notice.Fingerprint
+	}
+
+	var first *Frame
+	for _, frame := range notice.Backtrace {
+		if frame.Context == FrameContextApp {
+			first = frame
+			break
+		}
+	}
+	if first == nil && len(notice.Backtrace) > 0 {
+		first = notice.Backtrace[0]
+	}
+	if first == nil {
+		return notice.ErrorClass
+	}
+	return notice.ErrorClass + "|" + first.File + ":" + first.Number
+}
+
+// add records notice and reports whether it was collapsed into an earlier
+// repeat, returning the token of the notice it was collapsed into. When the
+// window ends, the collapsed repeats are passed to send.
+func (d *deduplicator) add(notice *Notice, window time.Duration, send func(*Notice)) (string, bool) {
+	key := dedupKey(notice)
+
+	d.Lock()
+	defer d.Unlock()
+
+	w, ok := d.windows[key]
+	if !ok {
+		w = &dedupWindow{}
+		d.windows[key] = w
+		time.AfterFunc(window, func() {
+			d.Lock()
+			delete(d.windows, key)
+			repeat := w.take()
+			d.Unlock()
+
+			if repeat != nil {
+				send(repeat)
+			}
+		})
+		return "", false
+	}
+
+	w.count++
+	if w.repeat == nil {
+		w.repeat = notice
+	}
+	return w.repeat.Token, true
+}
+
+// flush passes the repeats collapsed so far to send without waiting for
+// their windows to end.
+func (d *deduplicator) flush(send func(*Notice)) {
+	d.Lock()
+	var repeats []*Notice
+	for _, w := range d.windows {
+		if repeat := w.take(); repeat != nil {
+			repeats = append(repeats, repeat)
+		}
+	}
+	d.Unlock()
+
+	for _, repeat := range repeats {
+		send(repeat)
+	}
+}
+
+// take returns the collapsed repeat with its occurrence count, and resets the
+// window. It returns nil when there were no repeats.
+func (w *dedupWindow) take() *Notice {
+	repeat := w.repeat
+	if repeat == nil {
+		return nil
+	}
+	if repeat.Context == nil {
+		repeat.Context = Context{}
+	}
+	repeat.Context[occurrencesKey] = w.count
+
+	w.repeat = nil
+	w.count = 0
+	return repeat
+}
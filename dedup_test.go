@@ -0,0 +1,88 @@
+package honeybadger
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDedupKey(t *testing.T) {
+	notice := &Notice{
+		ErrorClass: "*errors.errorString",
+		Backtrace: []*Frame{
+			{File: "[GOROOT]/src/net/http/server.go", Number: "10"},
+			{File: "[PROJECT_ROOT]/main.go", Number: "42", Context: FrameContextApp},
+		},
+	}
+
+	if key := dedupKey(notice); key != "*errors.errorString|[PROJECT_ROOT]/main.go:42" {
+		t.Errorf("Expected key of class and first app frame. actual=%#v", key)
+	}
+
+	notice.Fingerprint = "cobras"
+	if key := dedupKey(notice); key != "cobras" {
+		t.Errorf("Expected fingerprint as key. actual=%#v", key)
+	}
+}
+
+func TestNotifyDedupWindow(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, DedupWindow: time.Hour})
+
+	err := errors.New("cobras")
+	notify := func() (string, error) { return client.Notify(err) }
+
+	first, _ := notify()
+	second, _ := notify()
+	third, _ := notify()
+	client.Notify("other", Fingerprint{"other"})
+
+	if second == first || third != second {
+		t.Errorf("Expected repeats to share the token of the first repeat. first=%#v second=%#v third=%#v", first, second, third)
+	}
+	if notices := backend.GetNotices(); len(notices) != 2 {
+		t.Fatalf("Expected repeats to be held until the window ends. actual=%d", len(notices))
+	}
+	if stats := client.Stats(); stats.Deduplicated != 2 {
+		t.Errorf("Expected deduplicated count. expected=%#v actual=%#v", 2, stats.Deduplicated)
+	}
+
+	client.Flush()
+
+	notices := backend.GetNotices()
+	if len(notices) != 3 {
+		t.Fatalf("Expected repeats to be sent on flush. actual=%d", len(notices))
+	}
+	if repeat := notices[2]; repeat.Token != second || repeat.Context[occurrencesKey] != 2 {
+		t.Errorf("Expected collapsed repeat with occurrences. token=%#v context=%#v", repeat.Token, repeat.Context)
+	}
+
+	client.Flush()
+	if notices := backend.GetNotices(); len(notices) != 3 {
+		t.Errorf("Expected no repeats to be sent twice. actual=%d", len(notices))
+	}
+}
+
+func TestNotifyDedupWindowEnds(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, DedupWindow: 10 * time.Millisecond})
+
+	err := errors.New("cobras")
+	notify := func() { client.Notify(err) }
+	notify()
+	notify()
+
+	deadline := time.Now().Add(time.Second)
+	for len(backend.GetNotices()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	notices := backend.GetNotices()
+	if len(notices) != 2 || notices[1].Context[occurrencesKey] != 1 {
+		t.Fatalf("Expected repeat to be sent when the window ends. actual=%d", len(notices))
+	}
+
+	notify()
+	if notices := backend.GetNotices(); len(notices) != 3 {
+		t.Errorf("Expected a new window to send immediately. actual=%d", len(notices))
+	}
+}
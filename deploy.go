@@ -0,0 +1,35 @@
+package honeybadger
+
+// Deploys is the feature for recording deploys.
+var Deploys = Feature{"deploys"}
+
+// Deploy describes a deploy recorded with Client.Deploy.
+type Deploy struct {
+	Revision      string `json:"revision,omitempty"`
+	Repository    string `json:"repository,omitempty"`
+	LocalUsername string `json:"local_username,omitempty"`
+	Environment   string `json:"environment,omitempty"`
+}
+
+func (d *Deploy) toJSON() []byte {
+	payload := hash{"deploy": d}
+	return payload.toJSON()
+}
+
+// Deploy records deploy with Honeybadger. The environment defaults to
+// Configuration.Env, and the revision to Configuration.Revision or the VCS
+// revision the binary was built from.
+func (client *Client) Deploy(deploy Deploy) error {
+	if deploy.Environment == "" {
+		deploy.Environment = client.Config.Env
+	}
+	if deploy.Revision == "" {
+		deploy.Revision = revision(client.Config)
+	}
+
+	if err := client.Config.Backend.Notify(Deploys, &deploy); err != nil {
+		client.Config.Logger.Printf("deploy error: %v\n", err)
+		return err
+	}
+	return nil
+}
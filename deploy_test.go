@@ -0,0 +1,62 @@
+package honeybadger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeploy(t *testing.T) {
+	var body hash
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertMethod(t, r, "POST")
+		if r.URL.Path != "/v1/deploys" {
+			t.Errorf("Unexpected path. actual=%#v", r.URL.Path)
+		}
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &body)
+		w.WriteHeader(201)
+	}))
+	defer ts.Close()
+
+	client := New(Configuration{APIKey: "badgers", Endpoint: ts.URL, Env: "production"})
+
+	err := client.Deploy(Deploy{Revision: "abc123", Repository: "git@github.com:honeybadger-io/honeybadger-go.git", LocalUsername: "badger"})
+	if err != nil {
+		t.Fatalf("Unexpected error. err=%v", err)
+	}
+
+	expected := map[string]interface{}{
+		"revision":       "abc123",
+		"repository":     "git@github.com:honeybadger-io/honeybadger-go.git",
+		"local_username": "badger",
+		"environment":    "production",
+	}
+	deploy, _ := body["deploy"].(map[string]interface{})
+	for k, v := range expected {
+		if deploy[k] != v {
+			t.Errorf("Unexpected deploy payload. key=%#v expected=%#v actual=%#v", k, v, deploy[k])
+		}
+	}
+}
+
+func TestDeployDefaults(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Env: "staging"})
+
+	client.Deploy(Deploy{})
+	client.Deploy(Deploy{Environment: "production", Revision: "abc123"})
+
+	deploys := backend.GetDeploys()
+	if len(deploys) != 2 {
+		t.Fatalf("Expected 2 deploys. actual=%d", len(deploys))
+	}
+	if deploys[0].Environment != "staging" || deploys[0].Revision != readBuildInfo().VCSRevision {
+		t.Errorf("Expected default environment and revision. actual=%#v", deploys[0])
+	}
+	if deploys[1].Environment != "production" || deploys[1].Revision != "abc123" {
+		t.Errorf("Expected explicit environment and revision. actual=%#v", deploys[1])
+	}
+}
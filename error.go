@@ -8,13 +8,37 @@ import (
 	"strconv"
 )
 
-const maxFrames = 20
+const (
+	// maxCallers is the number of stack frames captured for an error. The
+	// frames reported are limited separately by Configuration.MaxFrames.
+	maxCallers = 256
+	maxCauses  = 20
+)
+
+// wrapperClasses are the error types which only decorate the errors they wrap,
+// such as those returned by fmt.Errorf and errors.Join. They are skipped when
+// determining the class of an error.
+var wrapperClasses = map[string]bool{
+	"*fmt.wrapError":    true,
+	"*fmt.wrapErrors":   true,
+	"*errors.joinError": true,
+}
 
 // Frame represent a stack frame inside of a Honeybadger backtrace.
 type Frame struct {
-	Number string `json:"number"`
-	File   string `json:"file"`
-	Method string `json:"method"`
+	Number  string            `json:"number"`
+	File    string            `json:"file"`
+	Method  string            `json:"method"`
+	Source  map[string]string `json:"source,omitempty"`
+	Context string            `json:"context,omitempty"`
+}
+
+// Cause represents an error found while unwrapping the chain of a reported
+// error with errors.Unwrap or errors.Join.
+type Cause struct {
+	Class     string   `json:"class"`
+	Message   string   `json:"message"`
+	Backtrace []*Frame `json:"backtrace,omitempty"`
 }
 
 // Error provides more structured information about a Go error.
@@ -23,6 +47,7 @@ type Error struct {
 	Message string
 	Class   string
 	Stack   []*Frame
+	Causes  []*Cause
 }
 
 func (e Error) Unwrap() error {
@@ -37,6 +62,33 @@ type stacked interface {
 	Callers() []uintptr
 }
 
+// ContextProvider is implemented by errors which add Context to their
+// notices. The Context of every error in the chain is merged, with outer
+// errors taking precedence.
+type ContextProvider interface {
+	HoneybadgerContext() Context
+}
+
+// TagsProvider is implemented by errors which add Tags to their notices. The
+// Tags of every error in the chain are added.
+type TagsProvider interface {
+	HoneybadgerTags() Tags
+}
+
+// FingerprintProvider is implemented by errors which set the fingerprint of
+// their notices. The outermost error in the chain providing a non-empty
+// fingerprint is used.
+type FingerprintProvider interface {
+	HoneybadgerFingerprint() string
+}
+
+// ClassProvider is implemented by errors which set the class of their
+// notices. The outermost error in the chain providing a non-empty class is
+// used.
+type ClassProvider interface {
+	HoneybadgerClass() string
+}
+
 func NewError(msg interface{}) Error {
 	return newError(msg, 2)
 }
@@ -56,11 +108,112 @@ func newError(thing interface{}, stackOffset int) Error {
 	return Error{
 		err:     err,
 		Message: err.Error(),
-		Class:   reflect.TypeOf(err).String(),
+		Class:   errorClass(err),
 		Stack:   generateStack(autostack(err, stackOffset)),
+		Causes:  errorCauses(err),
 	}
 }
 
+// errorClass returns the type name of the outermost error in the chain which
+// is not a wrapper type.
+func errorClass(err error) string {
+	for {
+		if hbErr, ok := err.(Error); ok {
+			return hbErr.Class
+		}
+
+		class := reflect.TypeOf(err).String()
+		if !wrapperClasses[class] {
+			return class
+		}
+
+		children := unwrapErrors(err)
+		if len(children) == 0 {
+			return class
+		}
+		err = children[0]
+	}
+}
+
+// unwrapErrors returns the errors directly wrapped by err, supporting both
+// the Unwrap() error and Unwrap() []error conventions.
+func unwrapErrors(err error) []error {
+	var wrapped []error
+
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		wrapped = []error{u.Unwrap()}
+	case interface{ Unwrap() []error }:
+		wrapped = u.Unwrap()
+	}
+
+	var errs []error
+	for _, e := range wrapped {
+		if e != nil {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// errorCauses walks the tree of errors wrapped by err depth-first and returns
+// each of them as a Cause, up to maxCauses.
+func errorCauses(err error) []*Cause {
+	var causes []*Cause
+
+	var walk func(error)
+	walk = func(err error) {
+		for _, e := range unwrapErrors(err) {
+			if len(causes) >= maxCauses {
+				return
+			}
+
+			// An Error already describes the error it wraps, so continue
+			// the walk from the wrapped error's own causes.
+			if hbErr, ok := e.(Error); ok {
+				causes = append(causes, &Cause{
+					Class:     hbErr.Class,
+					Message:   hbErr.Message,
+					Backtrace: hbErr.Stack,
+				})
+				walk(hbErr.err)
+				continue
+			}
+
+			cause := &Cause{
+				Class:   errorClass(e),
+				Message: e.Error(),
+			}
+			if s, ok := e.(stacked); ok {
+				cause.Backtrace = generateStack(s.Callers())
+			}
+			causes = append(causes, cause)
+			walk(e)
+		}
+	}
+	walk(err)
+
+	return causes
+}
+
+// flattenErrors returns err and every error it wraps, outermost first.
+func flattenErrors(err error) []error {
+	var errs []error
+
+	var walk func(error)
+	walk = func(err error) {
+		errs = append(errs, err)
+		for _, e := range unwrapErrors(err) {
+			walk(e)
+		}
+	}
+	if err != nil {
+		walk(err)
+	}
+
+	return errs
+}
+
 func autostack(err error, offset int) []uintptr {
 	var s stacked
 
@@ -68,7 +221,7 @@ func autostack(err error, offset int) []uintptr {
 		return s.Callers()
 	}
 
-	stack := make([]uintptr, maxFrames)
+	stack := make([]uintptr, maxCallers)
 	length := runtime.Callers(2+offset, stack[:])
 	return stack[:length]
 }
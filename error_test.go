@@ -1,6 +1,7 @@
 package honeybadger
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
@@ -53,7 +54,7 @@ func (t customerror) Callers() []uintptr {
 }
 
 func newcustomerror() customerror {
-	stack := make([]uintptr, maxFrames)
+	stack := make([]uintptr, maxCallers)
 	length := runtime.Callers(1, stack[:])
 	return customerror{
 		error:   fmt.Errorf("hello world"),
@@ -91,3 +92,124 @@ func TestNewErrorCustomTrace(t *testing.T) {
 		}
 	}
 }
+
+type lookupError struct {
+	Key string
+}
+
+func (e *lookupError) Error() string {
+	return "lookup failed: " + e.Key
+}
+
+func TestNewErrorWrappedClass(t *testing.T) {
+	err := NewError(fmt.Errorf("load user: %w", &lookupError{Key: "42"}))
+
+	if err.Class != "*honeybadger.lookupError" {
+		t.Errorf("Expected class of wrapped error. expected=%#v actual=%#v", "*honeybadger.lookupError", err.Class)
+	}
+
+	if err.Message != "load user: lookup failed: 42" {
+		t.Errorf("Expected message of outermost error. expected=%#v actual=%#v", "load user: lookup failed: 42", err.Message)
+	}
+
+	if len(err.Causes) != 1 {
+		t.Fatalf("Expected one cause. actual=%#v", err.Causes)
+	}
+
+	if err.Causes[0].Class != "*honeybadger.lookupError" || err.Causes[0].Message != "lookup failed: 42" {
+		t.Errorf("Unexpected cause. actual=%#v", err.Causes[0])
+	}
+}
+
+func TestNewErrorJoinedCauses(t *testing.T) {
+	first := fmt.Errorf("first: %w", errors.New("root"))
+	second := newcustomerror()
+	err := NewError(errors.Join(first, second))
+
+	if err.Class != "*errors.errorString" {
+		t.Errorf("Expected class of first joined error. expected=%#v actual=%#v", "*errors.errorString", err.Class)
+	}
+
+	expected := []string{"first: root", "root", "hello world"}
+	if len(err.Causes) != len(expected) {
+		t.Fatalf("Unexpected number of causes. expected=%d actual=%#v", len(expected), err.Causes)
+	}
+
+	for i, message := range expected {
+		if err.Causes[i].Message != message {
+			t.Errorf("causes[%d].Message expected=%#v actual=%#v", i, message, err.Causes[i].Message)
+		}
+	}
+
+	if len(err.Causes[0].Backtrace) != 0 {
+		t.Errorf("Expected no backtrace for unstacked cause. actual=%#v", err.Causes[0].Backtrace)
+	}
+
+	if len(err.Causes[2].Backtrace) == 0 || !strings.HasSuffix(err.Causes[2].Backtrace[0].Method, ".newcustomerror") {
+		t.Errorf("Expected backtrace for stacked cause. actual=%#v", err.Causes[2].Backtrace)
+	}
+}
+
+func TestNewErrorNestedError(t *testing.T) {
+	inner := NewError(&lookupError{Key: "42"})
+	err := NewError(fmt.Errorf("outer: %w", inner))
+
+	if err.Class != "*honeybadger.lookupError" {
+		t.Errorf("Expected class of nested Error. expected=%#v actual=%#v", "*honeybadger.lookupError", err.Class)
+	}
+
+	if len(err.Causes) != 1 {
+		t.Fatalf("Expected nested Error to be reported once. actual=%#v", err.Causes)
+	}
+
+	if err.Causes[0].Class != "*honeybadger.lookupError" || len(err.Causes[0].Backtrace) == 0 {
+		t.Errorf("Expected nested Error class and stack. actual=%#v", err.Causes[0])
+	}
+}
+
+type domainError struct {
+	err         error
+	context     Context
+	tags        Tags
+	fingerprint string
+	class       string
+}
+
+func (e *domainError) Error() string                  { return "domain: " + e.err.Error() }
+func (e *domainError) Unwrap() error                  { return e.err }
+func (e *domainError) HoneybadgerContext() Context    { return e.context }
+func (e *domainError) HoneybadgerTags() Tags          { return e.tags }
+func (e *domainError) HoneybadgerFingerprint() string { return e.fingerprint }
+func (e *domainError) HoneybadgerClass() string       { return e.class }
+
+func TestNoticeErrorMetadata(t *testing.T) {
+	inner := &domainError{
+		err:         errors.New("Cobras!"),
+		context:     Context{"table": "users", "query": "inner"},
+		tags:        Tags{"db", "retryable"},
+		fingerprint: "inner",
+		class:       "DatabaseError",
+	}
+	outer := &domainError{
+		err:     fmt.Errorf("load user: %w", inner),
+		context: Context{"query": "outer"},
+		tags:    Tags{"users", "db"},
+	}
+
+	notice := newNotice(&Configuration{}, newError(outer, 0), Context{"request_id": "1"}, Tags{"explicit"})
+
+	if notice.Context["table"] != "users" || notice.Context["query"] != "outer" || notice.Context["request_id"] != "1" {
+		t.Errorf("Expected context from every error. actual=%#v", notice.Context)
+	}
+	if strings.Join(notice.Tags, ",") != "users,db,retryable,explicit" {
+		t.Errorf("Expected tags from every error. actual=%#v", notice.Tags)
+	}
+	if notice.Fingerprint != "inner" || notice.ErrorClass != "DatabaseError" {
+		t.Errorf("Expected fingerprint and class from error chain. actual=%q %q", notice.Fingerprint, notice.ErrorClass)
+	}
+
+	notice = newNotice(&Configuration{}, newError(outer, 0), Fingerprint{"explicit"}, ErrorClass{"Explicit"})
+	if notice.Fingerprint != "explicit" || notice.ErrorClass != "Explicit" {
+		t.Errorf("Expected extras to take precedence. actual=%q %q", notice.Fingerprint, notice.ErrorClass)
+	}
+}
@@ -6,12 +6,22 @@ import (
 )
 
 type eventPayload struct {
-	data map[string]any
+	data   map[string]any
+	limits payloadLimits
 }
 
+// toJSON limits each value of the event separately, so that its type and
+// timestamp are never truncated.
 func (e *eventPayload) toJSON() []byte {
-	h := hash(e.data)
-	return h.toJSON()
+	data := make(map[string]any, len(e.data))
+	for k, v := range e.data {
+		if k == "event_type" || k == "ts" {
+			data[k] = v
+		} else {
+			data[k] = userData{v}
+		}
+	}
+	return marshalPayload(data, e.limits)
 }
 
 func newEventPayload(eventType string, eventContext, eventData map[string]any) *eventPayload {
@@ -0,0 +1,249 @@
+package honeybadger
+
+import (
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// filteredValue replaces the values of filtered keys.
+const filteredValue = "[FILTERED]"
+
+// DefaultFilterKeys is the default value of Configuration.FilterKeys.
+var DefaultFilterKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"authorization",
+	"cookie",
+	`api[-_]?key`,
+}
+
+// filterPatterns caches the compiled form of each filter key.
+var filterPatterns sync.Map
+
+func compileFilterKey(key string) *regexp.Regexp {
+	if re, ok := filterPatterns.Load(key); ok {
+		return re.(*regexp.Regexp)
+	}
+
+	re, err := regexp.Compile("(?i)" + key)
+	if err != nil {
+		re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(key))
+	}
+	filterPatterns.Store(key, re)
+	return re
+}
+
+// keyFilter replaces the values of sensitive keys in notice data.
+type keyFilter struct {
+	patterns []*regexp.Regexp
+}
+
+func newKeyFilter(keys []string) *keyFilter {
+	f := &keyFilter{}
+	for _, key := range keys {
+		f.patterns = append(f.patterns, compileFilterKey(key))
+	}
+	return f
+}
+
+func (f *keyFilter) match(key string) bool {
+	if f == nil {
+		return false
+	}
+	for _, re := range f.patterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// hash returns a copy of h with sensitive keys filtered recursively.
+func (f *keyFilter) hash(h map[string]interface{}) map[string]interface{} {
+	return f.value(h).(map[string]interface{})
+}
+
+// hashIn filters h, where visiting holds the values containing h.
+func (f *keyFilter) hashIn(h map[string]interface{}, visiting map[visit]bool) map[string]interface{} {
+	if h == nil {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(h))
+	for k, v := range h {
+		if f.match(k) {
+			result[k] = filteredValue
+		} else {
+			result[k] = f.valueIn(v, visiting)
+		}
+	}
+	return result
+}
+
+// values returns a copy of values with sensitive keys filtered.
+func (f *keyFilter) values(values map[string][]string) map[string][]string {
+	if values == nil {
+		return nil
+	}
+
+	result := make(map[string][]string, len(values))
+	for k, v := range values {
+		if f.match(k) {
+			result[k] = []string{filteredValue}
+		} else {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// value returns a copy of v with sensitive keys filtered recursively. Values
+// which contain themselves are replaced with a placeholder.
+func (f *keyFilter) value(v interface{}) interface{} {
+	return f.valueIn(v, map[visit]bool{})
+}
+
+// valueIn filters v, where visiting holds the values containing v.
+func (f *keyFilter) valueIn(v interface{}, visiting map[visit]bool) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Pointer:
+		if rv.IsNil() {
+			break
+		}
+		key := visit{rv.UnsafePointer(), rv.Type()}
+		if visiting[key] {
+			return cyclePlaceholder
+		}
+		visiting[key] = true
+		defer delete(visiting, key)
+	}
+
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return f.hashIn(t, visiting)
+	case hash:
+		return hash(f.hashIn(t, visiting))
+	case *hash:
+		if t == nil {
+			return t
+		}
+		h := hash(f.hashIn(*t, visiting))
+		return &h
+	case Context:
+		return Context(f.hashIn(t, visiting))
+	case CGIData:
+		return CGIData(f.hashIn(t, visiting))
+	case Session:
+		return Session(f.hashIn(t, visiting))
+	case map[string]string:
+		if t == nil {
+			return nil
+		}
+		result := make(map[string]string, len(t))
+		for k, v := range t {
+			if f.match(k) {
+				result[k] = filteredValue
+			} else {
+				result[k] = v
+			}
+		}
+		return result
+	case Params:
+		return Params(f.values(t))
+	case url.Values:
+		return url.Values(f.values(t))
+	case map[string][]string:
+		return f.values(t)
+	case []interface{}:
+		result := make([]interface{}, len(t))
+		for i, e := range t {
+			result[i] = f.valueIn(e, visiting)
+		}
+		return result
+	}
+
+	// Other maps keyed by strings, slices, arrays and structs are filtered
+	// by reflection, following their JSON encoding, so that their sensitive
+	// values aren't serialized. Values which serialize themselves are kept.
+	if !rv.IsValid() {
+		return v
+	}
+	rt := rv.Type()
+	if rt.Implements(jsonMarshalerType) || rt.Implements(textMarshalerType) || rt.Implements(errorValueType) {
+		return v
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rt.Key().Kind() != reflect.String || rv.IsNil() {
+			return v
+		}
+		result := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			if f.match(k) {
+				result[k] = filteredValue
+			} else {
+				result[k] = f.valueIn(iter.Value().Interface(), visiting)
+			}
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if rt.Elem().Kind() == reflect.Uint8 || (rv.Kind() == reflect.Slice && rv.IsNil()) {
+			return v
+		}
+		result := make([]interface{}, rv.Len())
+		for i := range result {
+			result[i] = f.valueIn(rv.Index(i).Interface(), visiting)
+		}
+		return result
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return v
+		}
+		return f.valueIn(rv.Elem().Interface(), visiting)
+	case reflect.Struct:
+		result := make(map[string]interface{}, rv.NumField())
+		jsonFields(rv, func(name string, field reflect.Value) {
+			if f.match(name) {
+				result[name] = filteredValue
+			} else {
+				result[name] = f.valueIn(field.Interface(), visiting)
+			}
+		})
+		return result
+	}
+
+	return v
+}
+
+// url returns rawURL with the values of sensitive query parameters filtered.
+func (f *keyFilter) url(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+
+	query := u.Query()
+	filtered := false
+	for k := range query {
+		if f.match(k) {
+			query[k] = []string{filteredValue}
+			filtered = true
+		}
+	}
+	if !filtered {
+		return rawURL
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,161 @@
+package honeybadger
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestKeyFilterMatch(t *testing.T) {
+	f := newKeyFilter(DefaultFilterKeys)
+
+	for _, key := range []string{"password", "user_password", "HTTP_AUTHORIZATION", "HTTP_COOKIE", "csrf_token", "API-Key", "apikey"} {
+		if !f.match(key) {
+			t.Errorf("Expected key to be filtered. key=%q", key)
+		}
+	}
+
+	for _, key := range []string{"user_id", "HTTP_ACCEPT", "page"} {
+		if f.match(key) {
+			t.Errorf("Expected key not to be filtered. key=%q", key)
+		}
+	}
+}
+
+func TestKeyFilterValue(t *testing.T) {
+	f := newKeyFilter([]string{"secret", "^pin$", "[invalid"})
+
+	context := Context{
+		"user": map[string]any{
+			"name":   "badger",
+			"secret": "honey",
+			"cards":  []any{map[string]string{"pin": "1234", "last4": "4242"}},
+		},
+		"[invalid": "literal",
+		"spin":     "kept",
+	}
+
+	filtered := f.value(context).(Context)
+
+	user := filtered["user"].(map[string]any)
+	if user["name"] != "badger" || user["secret"] != filteredValue {
+		t.Errorf("Expected nested keys to be filtered. actual=%#v", user)
+	}
+
+	card := user["cards"].([]any)[0].(map[string]string)
+	if card["pin"] != filteredValue || card["last4"] != "4242" {
+		t.Errorf("Expected keys in slices to be filtered. actual=%#v", card)
+	}
+
+	if filtered["[invalid"] != filteredValue || filtered["spin"] != "kept" {
+		t.Errorf("Unexpected filtered context. actual=%#v", filtered)
+	}
+
+	if context["user"].(map[string]any)["secret"] != "honey" {
+		t.Errorf("Expected original context to be unchanged. actual=%#v", context)
+	}
+}
+
+func TestKeyFilterURL(t *testing.T) {
+	f := newKeyFilter(DefaultFilterKeys)
+
+	if got := f.url("/reset?token=abc&page=2"); got != "/reset?page=2&token=%5BFILTERED%5D" {
+		t.Errorf("Expected query to be filtered. actual=%q", got)
+	}
+
+	if got := f.url("/users?page=2&sort=name"); got != "/users?page=2&sort=name" {
+		t.Errorf("Expected URL without sensitive keys to be unchanged. actual=%q", got)
+	}
+}
+
+func TestNoticeFiltersRequest(t *testing.T) {
+	req := httptest.NewRequest("POST", "/login?token=abc", nil)
+	req.Header.Set("Authorization", "Bearer abc")
+	req.Header.Set("Cookie", "session=abc")
+	req.Header.Set("Accept", "application/json")
+	req.Form = url.Values{"username": {"badger"}, "password": {"honey"}}
+
+	notice := newNotice(newConfig(Configuration{}), newTestError(), req, Context{"api_key": "abc"})
+
+	var payload hash
+	if err := json.Unmarshal(notice.toJSON(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	request := payload["request"].(map[string]any)
+
+	cgi := request["cgi_data"].(map[string]any)
+	if cgi["HTTP_AUTHORIZATION"] != filteredValue || cgi["HTTP_COOKIE"] != filteredValue || cgi["HTTP_ACCEPT"] != "application/json" {
+		t.Errorf("Expected headers to be filtered. actual=%#v", cgi)
+	}
+
+	params := request["params"].(map[string]any)
+	if params["password"].([]any)[0] != filteredValue || params["username"].([]any)[0] != "badger" {
+		t.Errorf("Expected params to be filtered. actual=%#v", params)
+	}
+
+	if context := request["context"].(map[string]any); context["api_key"] != filteredValue {
+		t.Errorf("Expected context to be filtered. actual=%#v", context)
+	}
+
+	if request["url"] != "/login?token=%5BFILTERED%5D" {
+		t.Errorf("Expected URL to be filtered. actual=%#v", request["url"])
+	}
+
+	if notice.CGIData["HTTP_AUTHORIZATION"] != "Bearer abc" {
+		t.Errorf("Expected notice to keep original values for BeforeNotify. actual=%#v", notice.CGIData)
+	}
+}
+
+func TestKeyFilterCycle(t *testing.T) {
+	f := newKeyFilter(DefaultFilterKeys)
+
+	h := map[string]interface{}{"password": "badgers"}
+	h["self"] = h
+
+	filtered := f.hash(h)
+	if filtered["password"] != filteredValue || filtered["self"] != cyclePlaceholder {
+		t.Errorf("Expected cycle to be replaced. actual=%#v", filtered)
+	}
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string
+	Session  *loginSession `json:"session"`
+}
+
+type loginSession struct {
+	Token string `json:"token"`
+}
+
+func TestKeyFilterTypedValues(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true})
+
+	client.Notify("Cobras!", Context{
+		"users": []map[string]any{{"name": "badger", "password": "s1"}},
+		"req":   loginRequest{Email: "badger@example.com", Password: "s2", Session: &loginSession{Token: "s3"}},
+		"ctxs":  []Context{{"token": "s4"}},
+		"pairs": [1]map[string]string{{"secret": "s5"}},
+	})
+
+	out := string(backend.GetNotices()[0].toJSON())
+	for _, secret := range []string{"s1", "s2", "s3", "s4", "s5"} {
+		if strings.Contains(out, `"`+secret+`"`) {
+			t.Errorf("Expected value to be filtered. value=%q payload=%s", secret, out)
+		}
+	}
+
+	var payload hash
+	json.Unmarshal([]byte(out), &payload)
+	context := payload["request"].(map[string]interface{})["context"].(map[string]interface{})
+	req := context["req"].(map[string]interface{})
+	if req["email"] != "badger@example.com" || req["Password"] != filteredValue {
+		t.Errorf("Expected struct fields to be filtered by their JSON names. actual=%#v", req)
+	}
+	if user := context["users"].([]interface{})[0].(map[string]interface{}); user["name"] != "badger" {
+		t.Errorf("Expected other values to be kept. actual=%#v", user)
+	}
+}
@@ -0,0 +1,73 @@
+package honeybadger
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	uuidPattern   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	hexPattern    = regexp.MustCompile(`0[xX][0-9a-fA-F]+`)
+	numberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+)
+
+// messageTemplate replaces the UUIDs, hexadecimal and decimal numbers in
+// message with placeholders, so that messages which only differ by ids share
+// a template.
+func messageTemplate(message string) string {
+	message = uuidPattern.ReplaceAllString(message, "{uuid}")
+	message = hexPattern.ReplaceAllString(message, "{hex}")
+	return numberPattern.ReplaceAllString(message, "{n}")
+}
+
+// FingerprintByMessage is a Configuration.Fingerprinter which groups notices
+// by their class and message template, where numbers and UUIDs in the message
+// are normalized. For example, "user 42 not found" and "user 7 not found" are
+// grouped together.
+func FingerprintByMessage(notice *Notice) string {
+	return notice.ErrorClass + ":" + messageTemplate(notice.ErrorMessage)
+}
+
+// FingerprintByFrames returns a Configuration.Fingerprinter which groups
+// notices by their class and the file and method of the top n application
+// frames of their backtrace. When the backtrace has no application frames,
+// the top n frames are used instead.
+func FingerprintByFrames(n int) func(*Notice) string {
+	return func(notice *Notice) string {
+		frames := make([]*Frame, 0, n)
+		for _, frame := range notice.Backtrace {
+			if frame.Context == FrameContextApp {
+				frames = append(frames, frame)
+			}
+		}
+		if len(frames) == 0 {
+			frames = notice.Backtrace
+		}
+		if len(frames) > n {
+			frames = frames[:n]
+		}
+
+		parts := []string{notice.ErrorClass}
+		for _, frame := range frames {
+			parts = append(parts, frame.File+":"+frame.Method)
+		}
+		return strings.Join(parts, "|")
+	}
+}
+
+// FingerprintBySentinel returns a Configuration.Fingerprinter which groups
+// notices by the first of sentinels their error matches with errors.Is,
+// regardless of the errors wrapping it. Notices which match none of the
+// sentinels are not fingerprinted.
+func FingerprintBySentinel(sentinels ...error) func(*Notice) string {
+	return func(notice *Notice) string {
+		for _, sentinel := range sentinels {
+			if errors.Is(notice.Error, sentinel) {
+				return reflect.TypeOf(sentinel).String() + ":" + sentinel.Error()
+			}
+		}
+		return ""
+	}
+}
@@ -0,0 +1,76 @@
+package honeybadger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	tests := map[string]string{
+		"user 42 not found": "user {n} not found",
+		"order 6ba7b810-9dad-11d1-80b4-00c04fd430c8 is invalid": "order {uuid} is invalid",
+		"bad pointer 0xc000012345 after 1.5s":                   "bad pointer {hex} after {n}s",
+	}
+
+	for message, expected := range tests {
+		if got := messageTemplate(message); got != expected {
+			t.Errorf("messageTemplate(%q) expected=%q actual=%q", message, expected, got)
+		}
+	}
+}
+
+func TestFingerprintByMessage(t *testing.T) {
+	config := &Configuration{}
+	first := newNotice(config, newError(fmt.Errorf("user %d not found", 42), 0))
+	second := newNotice(config, newError(fmt.Errorf("user %d not found", 7), 0))
+
+	if FingerprintByMessage(first) != FingerprintByMessage(second) {
+		t.Errorf("Expected notices to share a fingerprint. actual=%q %q", FingerprintByMessage(first), FingerprintByMessage(second))
+	}
+}
+
+func TestFingerprintByFrames(t *testing.T) {
+	err := newTestError()
+	notice := newNotice(&Configuration{Root: "/path/to/root"}, err)
+
+	if got := FingerprintByFrames(2)(notice); got != "honeybadger|[PROJECT_ROOT]/badgers.go:badgers" {
+		t.Errorf("Expected application frames only. actual=%q", got)
+	}
+
+	notice = newNotice(&Configuration{}, err)
+	if got := FingerprintByFrames(1)(notice); got != "honeybadger|/path/to/root/badgers.go:badgers" {
+		t.Errorf("Expected top frames without application frames. actual=%q", got)
+	}
+}
+
+func TestFingerprintBySentinel(t *testing.T) {
+	fingerprinter := FingerprintBySentinel(io.ErrUnexpectedEOF, io.EOF)
+
+	notice := newNotice(&Configuration{}, newError(fmt.Errorf("read body: %w", io.EOF), 0))
+	if got := fingerprinter(notice); got != "*errors.errorString:EOF" {
+		t.Errorf("Expected sentinel fingerprint. actual=%q", got)
+	}
+
+	notice = newNotice(&Configuration{}, newError(errors.New("EOF"), 0))
+	if got := fingerprinter(notice); got != "" {
+		t.Errorf("Expected no fingerprint without a sentinel. actual=%q", got)
+	}
+}
+
+func TestNotifyWithFingerprinter(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, Fingerprinter: FingerprintByMessage})
+
+	client.Notify(fmt.Errorf("user %d not found", 42))
+	client.Notify(fmt.Errorf("user %d not found", 42), Fingerprint{"explicit"})
+
+	notices := backend.GetNotices()
+	if notices[0].Fingerprint != "*errors.errorString:user {n} not found" {
+		t.Errorf("Expected fingerprint from Fingerprinter. actual=%q", notices[0].Fingerprint)
+	}
+	if notices[1].Fingerprint != "explicit" {
+		t.Errorf("Expected explicit fingerprint to take precedence. actual=%q", notices[1].Fingerprint)
+	}
+}
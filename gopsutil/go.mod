@@ -0,0 +1,16 @@
+module github.com/honeybadger-io/honeybadger-go/gopsutil
+
+go 1.24.0
+
+require (
+	github.com/honeybadger-io/honeybadger-go v0.9.0
+	github.com/shirou/gopsutil v3.21.11+incompatible
+)
+
+require (
+	github.com/go-ole/go-ole v1.2.6 // indirect
+	github.com/google/uuid v1.0.0 // indirect
+	github.com/pborman/uuid v1.2.1 // indirect
+	github.com/yusufpapurcu/wmi v1.2.3 // indirect
+	golang.org/x/sys v0.36.0 // indirect
+)
@@ -0,0 +1,47 @@
+// Package hbgopsutil provides a honeybadger.StatsProvider which reports the
+// memory and load average of the host with gopsutil.
+package hbgopsutil
+
+import (
+	"github.com/honeybadger-io/honeybadger-go"
+	"github.com/shirou/gopsutil/load"
+	"github.com/shirou/gopsutil/mem"
+)
+
+type StatsProvider struct{}
+
+var _ honeybadger.StatsProvider = &StatsProvider{}
+
+// New creates a StatsProvider reporting host memory and load average.
+func New() *StatsProvider {
+	return &StatsProvider{}
+}
+
+func bytesToKB(bytes uint64) float64 {
+	return float64(bytes) / 1024.0
+}
+
+// Stats returns the "mem" stats of the host in KB, and the "load" average.
+func (*StatsProvider) Stats() map[string]interface{} {
+	stats := map[string]interface{}{}
+
+	if stat, err := mem.VirtualMemory(); err == nil {
+		stats["mem"] = map[string]interface{}{
+			"total":      bytesToKB(stat.Total),
+			"free":       bytesToKB(stat.Free),
+			"buffers":    bytesToKB(stat.Buffers),
+			"cached":     bytesToKB(stat.Cached),
+			"free_total": bytesToKB(stat.Free + stat.Buffers + stat.Cached),
+		}
+	}
+
+	if stat, err := load.Avg(); err == nil {
+		stats["load"] = map[string]interface{}{
+			"one":     stat.Load1,
+			"five":    stat.Load5,
+			"fifteen": stat.Load15,
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,143 @@
+package honeybadger
+
+import (
+	"bufio"
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Goroutine is a goroutine captured in a goroutine dump.
+type Goroutine struct {
+	ID          int      `json:"id"`
+	State       string   `json:"state"`
+	WaitMinutes int      `json:"wait_minutes,omitempty"`
+	Backtrace   []*Frame `json:"backtrace"`
+}
+
+// goroutineDump is passed to newNotice to attach the goroutines of a panic.
+type goroutineDump []*Goroutine
+
+// captureGoroutines returns the stacks of all goroutines, reading at most
+// maxBytes of runtime.Stack output.
+func captureGoroutines(maxBytes int) goroutineDump {
+	if maxBytes <= 0 {
+		return nil
+	}
+
+	buf := make([]byte, maxBytes)
+	n := runtime.Stack(buf, true)
+	return parseGoroutines(buf[:n])
+}
+
+// parseGoroutines parses the output of runtime.Stack. Each goroutine starts
+// with a header such as "goroutine 7 [chan receive, 3 minutes]:" followed by
+// pairs of function and file lines. Truncated output yields a partial final
+// goroutine rather than an error.
+func parseGoroutines(dump []byte) goroutineDump {
+	var (
+		goroutines goroutineDump
+		current    *Goroutine
+		method     string
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(dump))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "goroutine "):
+			current = parseGoroutineHeader(line)
+			if current != nil {
+				goroutines = append(goroutines, current)
+			}
+			method = ""
+		case current == nil || line == "":
+			continue
+		case strings.HasPrefix(line, "\t"):
+			if method == "" {
+				continue
+			}
+			file, number := parseGoroutineFileLine(strings.TrimPrefix(line, "\t"))
+			current.Backtrace = append(current.Backtrace, &Frame{
+				File:   file,
+				Number: number,
+				Method: method,
+			})
+			method = ""
+		case strings.HasPrefix(line, "..."):
+			// "...additional frames elided..."
+			method = ""
+		default:
+			method = parseGoroutineMethod(line)
+		}
+	}
+
+	return goroutines
+}
+
+func parseGoroutineHeader(line string) *Goroutine {
+	rest := strings.TrimPrefix(line, "goroutine ")
+	idText, rest, ok := strings.Cut(rest, " [")
+	if !ok {
+		return nil
+	}
+
+	id, err := strconv.Atoi(idText)
+	if err != nil {
+		return nil
+	}
+
+	goroutine := &Goroutine{ID: id}
+
+	rest, _, _ = strings.Cut(rest, "]")
+	for i, part := range strings.Split(rest, ", ") {
+		if i == 0 {
+			goroutine.State = part
+			continue
+		}
+		if minutes, ok := strings.CutSuffix(part, " minutes"); ok {
+			goroutine.WaitMinutes, _ = strconv.Atoi(minutes)
+		}
+	}
+
+	return goroutine
+}
+
+// parseGoroutineMethod strips the argument list from a function line, such as
+// "main.(*T).run(0xc000010000, 0x1)".
+func parseGoroutineMethod(line string) string {
+	if method, ok := strings.CutPrefix(line, "created by "); ok {
+		method, _, _ = strings.Cut(method, " in goroutine ")
+		return method
+	}
+
+	if i := strings.LastIndex(line, "("); i > 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// parseGoroutineFileLine splits a file line such as "/app/main.go:12 +0x1d"
+// into its file and line number.
+func parseGoroutineFileLine(line string) (string, string) {
+	line, _, _ = strings.Cut(line, " +0x")
+	if i := strings.LastIndex(line, ":"); i > 0 {
+		return line[:i], line[i+1:]
+	}
+	return line, ""
+}
+
+func composeGoroutines(goroutines goroutineDump, config *Configuration) []*Goroutine {
+	result := make([]*Goroutine, 0, len(goroutines))
+	for _, goroutine := range goroutines {
+		result = append(result, &Goroutine{
+			ID:          goroutine.ID,
+			State:       goroutine.State,
+			WaitMinutes: goroutine.WaitMinutes,
+			Backtrace:   composeStack(goroutine.Backtrace, config),
+		})
+	}
+	return result
+}
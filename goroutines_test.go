@@ -0,0 +1,91 @@
+package honeybadger
+
+import "testing"
+
+const testGoroutineDump = `goroutine 1 [running]:
+main.main.func1()
+	/app/main.go:12 +0x1d
+main.(*Server).run(0xc000010000, {0x4a5f20, 0x1})
+	/app/server.go:40 +0x45
+
+goroutine 7 [chan receive, 3 minutes]:
+main.worker(0xc00001e0c0)
+	/app/worker.go:20 +0x2c
+created by main.main in goroutine 1
+	/app/main.go:9 +0x6f
+
+goroutine 9 [select, locked to thread]:
+runtime.gopark(0x0?, 0x0?, 0x0?, 0x0?, 0x0?)
+	/usr/local/go/src/runtime/proc.go:424 +0xce
+...additional frames elided...
+
+goroutine 11 [sleep]:
+time.Sleep(0x3b9aca00)
+	/usr/local/go/src/runt`
+
+func TestParseGoroutines(t *testing.T) {
+	goroutines := parseGoroutines([]byte(testGoroutineDump))
+	if len(goroutines) != 4 {
+		t.Fatalf("Expected 4 goroutines. actual=%d", len(goroutines))
+	}
+
+	first := goroutines[0]
+	if first.ID != 1 || first.State != "running" || len(first.Backtrace) != 2 {
+		t.Errorf("Unexpected first goroutine. actual=%#v", first)
+	}
+	if frame := first.Backtrace[1]; frame.Method != "main.(*Server).run" || frame.File != "/app/server.go" || frame.Number != "40" {
+		t.Errorf("Unexpected frame. actual=%#v", frame)
+	}
+
+	second := goroutines[1]
+	if second.ID != 7 || second.State != "chan receive" || second.WaitMinutes != 3 {
+		t.Errorf("Unexpected second goroutine. actual=%#v", second)
+	}
+	if len(second.Backtrace) != 2 || second.Backtrace[1].Method != "main.main" || second.Backtrace[1].Number != "9" {
+		t.Errorf("Expected creator frame. actual=%#v", second.Backtrace)
+	}
+
+	if third := goroutines[2]; third.State != "select" || len(third.Backtrace) != 1 {
+		t.Errorf("Unexpected third goroutine. actual=%#v", third)
+	}
+
+	if last := goroutines[3]; last.ID != 11 || last.Backtrace[0].File != "/usr/local/go/src/runt" {
+		t.Errorf("Expected truncated goroutine to be parsed. actual=%#v", last)
+	}
+}
+
+func TestCaptureGoroutines(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() { <-done }()
+
+	goroutines := captureGoroutines(1 << 20)
+	if len(goroutines) < 2 {
+		t.Fatalf("Expected multiple goroutines. actual=%d", len(goroutines))
+	}
+	if goroutines[0].State != "running" || len(goroutines[0].Backtrace) == 0 {
+		t.Errorf("Expected current goroutine first. actual=%#v", goroutines[0])
+	}
+
+	if goroutines := captureGoroutines(0); goroutines != nil {
+		t.Errorf("Expected no goroutines without a size. actual=%#v", goroutines)
+	}
+}
+
+func TestMonitorGoroutineDump(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, GoroutineDump: true})
+
+	func() {
+		defer func() { _ = recover() }()
+		defer client.Monitor()
+		panic("Cobras!")
+	}()
+
+	if len(backend.GetNotices()) != 1 {
+		t.Fatalf("Expected 1 notice. actual=%d", len(backend.GetNotices()))
+	}
+	if goroutines := backend.GetNotices()[0].Goroutines; len(goroutines) == 0 || len(goroutines[0].Backtrace) == 0 {
+		t.Errorf("Expected notice to include goroutines. actual=%#v", goroutines)
+	}
+}
@@ -1,7 +1,7 @@
 package honeybadger
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 	"net/url"
 )
@@ -38,6 +38,9 @@ type CGIData hash
 // Params stores the form or url values from an HTTP request.
 type Params url.Values
 
+// Session stores the session data of the request in which an error occurred.
+type Session hash
+
 // Tags represents tags of the error which is classified errors in Honeybadger.
 type Tags []string
 
@@ -45,11 +48,7 @@ type Tags []string
 type hash map[string]interface{}
 
 func (h *hash) toJSON() []byte {
-	out, err := json.Marshal(h)
-	if err == nil {
-		return out
-	}
-	panic(err)
+	return marshalPayload(h, defaultPayloadLimits)
 }
 
 // Configure updates configuration of the global client.
@@ -67,6 +66,16 @@ func ClearContext() {
 	DefaultClient.ClearContext()
 }
 
+// SetUser sets the user sent with all notices of the global client.
+func SetUser(user User) {
+	DefaultClient.SetUser(user)
+}
+
+// ClearUser clears the user of the global client.
+func ClearUser() {
+	DefaultClient.ClearUser()
+}
+
 // SetEventContext sets context data that will be merged into all events sent
 // via Event(). Data passed directly to Event() takes precedence over context.
 func SetEventContext(c Context) {
@@ -78,17 +87,47 @@ func ClearEventContext() {
 	DefaultClient.ClearEventContext()
 }
 
+// AddBreadcrumb records a breadcrumb on the global client which is sent with
+// subsequent notices.
+func AddBreadcrumb(message, category string, metadata map[string]any) {
+	DefaultClient.AddBreadcrumb(message, category, metadata)
+}
+
+// AddBreadcrumbContext records a breadcrumb on the trail of ctx created by
+// WithBreadcrumbs, or on the global client when ctx has no trail.
+func AddBreadcrumbContext(ctx context.Context, message, category string, metadata map[string]any) {
+	DefaultClient.AddBreadcrumbContext(ctx, message, category, metadata)
+}
+
+// ClearBreadcrumbs clears the breadcrumbs recorded on the global client.
+func ClearBreadcrumbs() {
+	DefaultClient.ClearBreadcrumbs()
+}
+
 // Notify reports the error err to the Honeybadger service.
 //
 // The first argument err may be an error, a string, or any other type in which
 // case its formatted value will be used.
 //
 // It returns a string UUID which can be used to reference the error from the
-// Honeybadger service, and an error as a second argument.
+// Honeybadger service, and an error as a second argument. Notices which are
+// ignored by Configuration.IgnoreErrors or a BeforeNotify handler return an
+// empty string and ErrNoticeIgnored, which isn't a failure. Notices sampled
+// out by Configuration.SampleRates return an empty string and a nil error.
 func Notify(err interface{}, extra ...interface{}) (string, error) {
 	return DefaultClient.Notify(newError(err, 2), extra...)
 }
 
+// NotifyContext reports the error err to the Honeybadger service like Notify,
+// including the Context, Tags, fingerprint and breadcrumbs stored in ctx by
+// WithContext, WithTags, WithFingerprint and WithBreadcrumbs. For example:
+//
+//	ctx = honeybadger.WithContext(ctx, honeybadger.Context{"user_id": 1})
+//	honeybadger.NotifyContext(ctx, err)
+func NotifyContext(ctx context.Context, err interface{}, extra ...interface{}) (string, error) {
+	return DefaultClient.NotifyContext(ctx, newError(err, 2), extra...)
+}
+
 // Event sends a custom event to Honeybadger Insights. For example:
 //
 //	honeybadger.Event("user_login", map[string]any{
@@ -112,7 +151,7 @@ func Event(eventType string, eventData map[string]any) error {
 // still up to the user to recover from panics if desired.
 func Monitor() {
 	if err := recover(); err != nil {
-		DefaultClient.Notify(newError(err, 2))
+		DefaultClient.Notify(newError(err, 2), DefaultClient.panicExtra()...)
 		DefaultClient.Flush()
 		panic(err)
 	}
@@ -124,6 +163,33 @@ func Flush() {
 	DefaultClient.Flush()
 }
 
+// CheckIn reports the check-in id to Honeybadger.
+func CheckIn(id string) error {
+	return DefaultClient.CheckIn(id)
+}
+
+// CheckInBySlug reports the check-in with the slug configured in the project
+// of Config.APIKey to Honeybadger.
+func CheckInBySlug(slug string) error {
+	return DefaultClient.CheckInBySlug(slug)
+}
+
+// RunCheckIn runs job and reports the check-in id when it returns a nil
+// error. Otherwise the error is reported as a notice and returned. For
+// example:
+//
+//	err := honeybadger.RunCheckIn("1MqIo1", func() error {
+//		return sendInvoices()
+//	})
+func RunCheckIn(id string, job func() error) error {
+	return DefaultClient.RunCheckIn(id, job)
+}
+
+// Stats returns the counters of the default client.
+func Stats() ClientStats {
+	return DefaultClient.Stats()
+}
+
 // Handler returns an http.Handler function which automatically reports panics
 // to Honeybadger and then re-panics.
 func Handler(h http.Handler) http.Handler {
@@ -132,7 +198,9 @@ func Handler(h http.Handler) http.Handler {
 
 // BeforeNotify adds a callback function which is run before a notice is
 // reported to Honeybadger. If any function returns an error the notification
-// will be skipped, otherwise it will be sent.
+// will be skipped, otherwise it will be sent. Returning ErrNoticeIgnored
+// ignores the notice, and Notify returns ErrNoticeIgnored rather than a
+// failure.
 func BeforeNotify(handler func(notice *Notice) error) {
 	DefaultClient.BeforeNotify(handler)
 }
@@ -181,6 +181,13 @@ func teardown() {
 	DefaultClient.beforeEventHandlers = nil
 	DefaultClient.context = newContextSync()
 	DefaultClient.eventContext = newContextSync()
+	DefaultClient.breadcrumbs = newBreadcrumbs()
+	DefaultClient.dedup = newDeduplicator()
+	DefaultClient.limiter = &rateLimiter{}
+	DefaultClient.stats = &clientStats{}
+	DefaultClient.statsSampler.Stop()
+	DefaultClient.statsSampler = newStatsSampler(DefaultClient.Config.StatsProvider, DefaultClient.Config.StatsInterval)
+	DefaultClient.ClearUser()
 }
 
 func TestDefaultConfig(t *testing.T) {
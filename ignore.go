@@ -0,0 +1,68 @@
+package honeybadger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+)
+
+// ErrNoticeIgnored is returned by Notify, with an empty token, for notices
+// which are ignored by Configuration.IgnoreErrors or a BeforeNotify handler.
+// It doesn't indicate a failure, and may be checked with errors.Is. A
+// BeforeNotify handler returns it to ignore a notice.
+var ErrNoticeIgnored = errors.New("notice ignored")
+
+// DefaultIgnoreErrors is the default value of Configuration.IgnoreErrors.
+var DefaultIgnoreErrors = []interface{}{
+	context.Canceled,
+	http.ErrAbortHandler,
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ignoreError reports whether err matches any of rules. Class names are
+// matched against the class of err, the classes provided by its chain and the
+// ErrorClass values in extra, so that errors can be ignored before their
+// notice is built. See Configuration.IgnoreErrors for the supported rules.
+func ignoreError(err Error, extra []interface{}, rules []interface{}) bool {
+	for _, rule := range rules {
+		switch r := rule.(type) {
+		case error:
+			if errors.Is(err, r) {
+				return true
+			}
+		case reflect.Type:
+			if (r.Kind() == reflect.Interface || r.Implements(errorType)) && errors.As(err, reflect.New(r).Interface()) {
+				return true
+			}
+		case string:
+			if slices.Contains(errorClasses(err, extra), r) {
+				return true
+			}
+		case *regexp.Regexp:
+			if r.MatchString(err.Message) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// errorClasses returns the class names a notice of err may be reported with.
+func errorClasses(err Error, extra []interface{}) []string {
+	classes := []string{err.Class}
+	for _, e := range flattenErrors(err.err) {
+		if p, ok := e.(ClassProvider); ok {
+			classes = append(classes, p.HoneybadgerClass())
+		}
+	}
+	for _, thing := range extra {
+		if class, ok := thing.(ErrorClass); ok {
+			classes = append(classes, class.Name)
+		}
+	}
+	return classes
+}
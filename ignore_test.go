@@ -0,0 +1,94 @@
+package honeybadger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestIgnoreError(t *testing.T) {
+	rules := []interface{}{
+		io.EOF,
+		reflect.TypeOf((*lookupError)(nil)),
+		"*net.OpError",
+		regexp.MustCompile(`^broken pipe`),
+		reflect.TypeOf(""), // ignored: not an error type
+	}
+
+	tests := []struct {
+		err     error
+		ignored bool
+	}{
+		{fmt.Errorf("read: %w", io.EOF), true},
+		{fmt.Errorf("load user: %w", &lookupError{Key: "42"}), true},
+		{errors.New("broken pipe to client"), true},
+		{errors.New("connection reset"), false},
+		{io.ErrUnexpectedEOF, false},
+	}
+
+	for _, tt := range tests {
+		if ignored := ignoreError(newError(tt.err, 0), nil, rules); ignored != tt.ignored {
+			t.Errorf("ignoreError(%q) expected=%v actual=%v", tt.err, tt.ignored, ignored)
+		}
+	}
+
+	if !ignoreError(newError(errors.New("Cobras!"), 0), []interface{}{ErrorClass{"*net.OpError"}}, rules) {
+		t.Errorf("Expected error to be ignored by class name.")
+	}
+}
+
+func TestNotifyIgnoredErrors(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true})
+
+	token, err := client.Notify(fmt.Errorf("request: %w", context.Canceled))
+	if token != "" || !errors.Is(err, ErrNoticeIgnored) {
+		t.Errorf("Expected ignored notice. token=%q err=%v", token, err)
+	}
+
+	token, err = client.Notify(http.ErrAbortHandler)
+	if token != "" || !errors.Is(err, ErrNoticeIgnored) {
+		t.Errorf("Expected ignored notice. token=%q err=%v", token, err)
+	}
+
+	client.BeforeNotify(func(notice *Notice) error {
+		return fmt.Errorf("skip: %w", ErrNoticeIgnored)
+	})
+	token, err = client.Notify(errors.New("Cobras!"))
+	if token != "" || !errors.Is(err, ErrNoticeIgnored) {
+		t.Errorf("Expected notice ignored by handler. token=%q err=%v", token, err)
+	}
+
+	if notices := backend.GetNotices(); len(notices) != 0 {
+		t.Errorf("Expected no notices to be sent. actual=%d", len(notices))
+	}
+
+	client = New(Configuration{Backend: backend, Sync: true, IgnoreErrors: []interface{}{}})
+	if token, _ := client.Notify(context.Canceled); token == "" {
+		t.Errorf("Expected notice to be sent without ignore rules.")
+	}
+}
+
+func TestNotifyIgnoredBeforeBuilding(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, IgnoreErrors: []interface{}{"ValidationError"}})
+
+	var handled int
+	client.BeforeNotify(func(notice *Notice) error {
+		handled++
+		return nil
+	})
+
+	token, err := client.Notify(&domainError{err: errors.New("invalid email"), class: "ValidationError"})
+	if token != "" || !errors.Is(err, ErrNoticeIgnored) {
+		t.Errorf("Expected error to be ignored by its provided class. token=%q err=%v", token, err)
+	}
+	if handled != 0 {
+		t.Errorf("Expected no notice to be built for an ignored error. handled=%d", handled)
+	}
+}
@@ -0,0 +1,84 @@
+package honeybadger
+
+import (
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// truncatedMarker marks the values of payloads which were truncated. It is
+// appended to truncated strings and slices, and is the key of the number of
+// entries dropped from truncated maps.
+const truncatedMarker = "[TRUNCATED]"
+
+// The smallest limits used when shrinking a payload to fit its size limit.
+const (
+	minStringLength = 64
+	minEntries      = 4
+)
+
+// payloadLimits bounds the values serialized in a payload. A limit of 0 is
+// unlimited.
+type payloadLimits struct {
+	maxStringLength int
+	maxEntries      int
+	maxDepth        int
+	maxBytes        int
+
+	// truncated counts the payloads which were truncated, if set.
+	truncated *atomic.Int64
+}
+
+// defaultPayloadLimits applies outside the user data of payloads, and to
+// payloads which aren't limited by the configuration of a client.
+var defaultPayloadLimits = payloadLimits{maxDepth: maxPayloadDepth}
+
+func newPayloadLimits(config *Configuration, truncated *atomic.Int64) payloadLimits {
+	return payloadLimits{
+		maxStringLength: config.MaxStringLength,
+		maxEntries:      config.MaxCollectionEntries,
+		maxDepth:        config.MaxDepth,
+		maxBytes:        config.MaxPayloadBytes,
+		truncated:       truncated,
+	}
+}
+
+// shrink halves the string and collection limits of l, so that a payload
+// which exceeds maxBytes can be serialized again smaller. It reports false
+// when the limits can't shrink any further.
+func (l *payloadLimits) shrink() bool {
+	shrunk := false
+
+	switch {
+	case l.maxStringLength <= 0:
+		l.maxStringLength = max(l.maxBytes/2, minStringLength)
+		shrunk = true
+	case l.maxStringLength > minStringLength:
+		l.maxStringLength = max(l.maxStringLength/2, minStringLength)
+		shrunk = true
+	}
+
+	switch {
+	case l.maxEntries <= 0:
+		l.maxEntries = 100
+		shrunk = true
+	case l.maxEntries > minEntries:
+		l.maxEntries = max(l.maxEntries/2, minEntries)
+		shrunk = true
+	}
+
+	return shrunk
+}
+
+// truncateString returns s limited to maxStringLength bytes, cut at a rune
+// boundary, and reports whether it was truncated.
+func (l payloadLimits) truncateString(s string) (string, bool) {
+	if l.maxStringLength <= 0 || len(s) <= l.maxStringLength {
+		return s, false
+	}
+
+	cut := l.maxStringLength
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + truncatedMarker, true
+}
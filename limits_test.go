@@ -0,0 +1,165 @@
+package honeybadger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func limitedJSON(t *testing.T, value interface{}, limits payloadLimits) map[string]interface{} {
+	var out map[string]interface{}
+	if err := json.Unmarshal(marshalPayload(userData{value}, limits), &out); err != nil {
+		t.Fatalf("Expected valid JSON. err=%v", err)
+	}
+	return out
+}
+
+func TestTruncateString(t *testing.T) {
+	limits := payloadLimits{maxStringLength: 4}
+
+	if s, truncated := limits.truncateString("abc"); s != "abc" || truncated {
+		t.Errorf("Expected short string to be kept. actual=%#v", s)
+	}
+	if s, truncated := limits.truncateString("abcdef"); s != "abcd"+truncatedMarker || !truncated {
+		t.Errorf("Expected string to be truncated. actual=%#v", s)
+	}
+	// "é" is two bytes, which must not be split.
+	if s, _ := limits.truncateString("abcé"); s != "abc"+truncatedMarker {
+		t.Errorf("Expected string to be cut at a rune boundary. actual=%#v", s)
+	}
+}
+
+func TestPayloadLimits(t *testing.T) {
+	out := limitedJSON(t, hash{
+		"string": strings.Repeat("x", 10),
+		"slice":  []int{1, 2, 3, 4},
+		"map":    map[string]int{"a": 1, "b": 2, "c": 3},
+		"nested": hash{"a": hash{"b": hash{}}},
+	}, payloadLimits{maxStringLength: 5, maxEntries: 4, maxDepth: 3})
+
+	if out["string"] != "xxxxx"+truncatedMarker {
+		t.Errorf("Expected truncated string. actual=%#v", out["string"])
+	}
+	if slice := out["slice"].([]interface{}); len(slice) != 4 {
+		t.Errorf("Expected slice within limit to be kept. actual=%#v", slice)
+	}
+	if nested := out["nested"].(map[string]interface{})["a"].(map[string]interface{}); nested["b"] != maxDepthPlaceholder {
+		t.Errorf("Expected max depth placeholder. actual=%#v", nested)
+	}
+
+	out = limitedJSON(t, hash{"slice": []int{1, 2, 3}, "map": map[string]int{"a": 1, "b": 2, "c": 3}}, payloadLimits{maxEntries: 2})
+
+	if slice := out["slice"].([]interface{}); len(slice) != 3 || slice[2] != truncatedMarker {
+		t.Errorf("Expected truncated slice. actual=%#v", slice)
+	}
+	m := out["map"].(map[string]interface{})
+	if len(m) != 3 || m["a"] != 1.0 || m["b"] != 2.0 || m[truncatedMarker] != 1.0 {
+		t.Errorf("Expected truncated map. actual=%#v", m)
+	}
+}
+
+func TestPayloadMaxBytes(t *testing.T) {
+	payload := hash{"strings": []string{strings.Repeat("x", 1000), strings.Repeat("y", 1000)}}
+
+	out := marshalPayload(userData{payload}, payloadLimits{maxBytes: 500})
+	if len(out) > 500 {
+		t.Errorf("Expected payload within max bytes. actual=%d", len(out))
+	}
+	if !json.Valid(out) {
+		t.Errorf("Expected valid JSON. actual=%s", out)
+	}
+}
+
+func TestNotifyTruncation(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, MaxStringLength: 8})
+
+	client.Notify("Cobras!", Context{"long": strings.Repeat("x", 100)})
+
+	var payload hash
+	json.Unmarshal(backend.GetNotices()[0].toJSON(), &payload)
+	assertContext(t, payload, Context{"long": "xxxxxxxx" + truncatedMarker})
+
+	if stats := client.Stats(); stats.Truncated != 1 {
+		t.Errorf("Expected truncated notice to be counted. expected=%#v actual=%#v", 1, stats.Truncated)
+	}
+
+	// Events are limited by the client which sent them.
+	event := newEventPayload("log", nil, map[string]any{"message": strings.Repeat("y", 100)})
+	event.limits = newPayloadLimits(client.Config, &client.stats.truncated)
+	var data map[string]interface{}
+	json.Unmarshal(event.toJSON(), &data)
+	if data["message"] != "yyyyyyyy"+truncatedMarker {
+		t.Errorf("Expected truncated event data. actual=%#v", data["message"])
+	}
+	if stats := client.Stats(); stats.Truncated != 2 {
+		t.Errorf("Expected truncated event to be counted. expected=%#v actual=%#v", 2, stats.Truncated)
+	}
+}
+
+func TestNoticeLimitsUserData(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, MaxStringLength: 8, MaxDepth: 2})
+	client.AddBreadcrumb("Loaded user", "query", map[string]any{"sql": strings.Repeat("s", 100)})
+
+	message := strings.Repeat("m", 100)
+	client.Notify(message, Context{
+		"nested": hash{"a": "b"},
+		"deep":   hash{"a": hash{"b": "c"}},
+		"raw":    json.RawMessage(`"` + strings.Repeat("r", 100) + `"`),
+	})
+
+	var payload hash
+	json.Unmarshal(backend.GetNotices()[0].toJSON(), &payload)
+
+	if e := payload["error"].(map[string]interface{}); e["message"] != message {
+		t.Errorf("Expected error message not to be truncated. actual=%#v", e["message"])
+	}
+	backtrace := payload["error"].(map[string]interface{})["backtrace"].([]interface{})
+	if file := backtrace[0].(map[string]interface{})["file"].(string); strings.HasSuffix(file, truncatedMarker) {
+		t.Errorf("Expected backtrace not to be truncated. actual=%#v", file)
+	}
+
+	// Depth is measured from the root of the context.
+	context := payload["request"].(map[string]interface{})["context"].(map[string]interface{})
+	if nested := context["nested"].(map[string]interface{}); nested["a"] != "b" {
+		t.Errorf("Expected nested context within max depth. actual=%#v", nested)
+	}
+	if deep := context["deep"].(map[string]interface{}); deep["a"] != maxDepthPlaceholder {
+		t.Errorf("Expected max depth placeholder. actual=%#v", deep)
+	}
+	if context["raw"] != `"rrrrrrr`+truncatedMarker {
+		t.Errorf("Expected marshaled value to be truncated. actual=%#v", context["raw"])
+	}
+
+	trail := payload["breadcrumbs"].(map[string]interface{})["trail"].([]interface{})
+	crumb := trail[0].(map[string]interface{})
+	if crumb["message"] != "Loaded user" || crumb["metadata"].(map[string]interface{})["sql"] != "ssssssss"+truncatedMarker {
+		t.Errorf("Expected only breadcrumb metadata to be truncated. actual=%#v", crumb)
+	}
+}
+
+func TestNoticeMaxPayloadBytes(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, MaxPayloadBytes: 16 * 1024})
+
+	context := Context{}
+	for i := 0; i < 100; i++ {
+		context[strings.Repeat("k", i+1)] = strings.Repeat("v", 1000)
+	}
+	message := strings.Repeat("m", 1000)
+	client.Notify(NewError(message), ErrorClass{"HugeError"}, context)
+
+	notice := backend.GetNotices()[0]
+	out := notice.toJSON()
+	if len(out) > 16*1024 {
+		t.Errorf("Expected notice within max bytes. actual=%d", len(out))
+	}
+
+	var payload hash
+	json.Unmarshal(out, &payload)
+	e := payload["error"].(map[string]interface{})
+	if e["message"] != message || e["token"] != notice.Token || e["class"] != "HugeError" {
+		t.Errorf("Expected shrunk notice to keep its message, token and class. actual=%#v", e)
+	}
+}
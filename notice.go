@@ -1,17 +1,15 @@
 package honeybadger
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 	"net/url"
 	"os"
-	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/pborman/uuid"
-	"github.com/shirou/gopsutil/load"
-	"github.com/shirou/gopsutil/mem"
 )
 
 // ErrorClass represents the class name of the error which is sent to
@@ -20,6 +18,18 @@ type ErrorClass struct {
 	Name string
 }
 
+// Component represents the component of the application in which the error
+// occurred, such as the route of an HTTP request.
+type Component struct {
+	Name string
+}
+
+// Action represents the action within the Component in which the error
+// occurred, such as the method of an HTTP request.
+type Action struct {
+	Name string
+}
+
 // Fingerprint represents the fingerprint of the error, which controls grouping
 // in Honeybadger.
 type Fingerprint struct {
@@ -42,16 +52,31 @@ type Notice struct {
 	Hostname     string
 	Env          string
 	Backtrace    []*Frame
+	Causes       []*Cause
+	Goroutines   []*Goroutine
+	Breadcrumbs  []*Breadcrumb
 	ProjectRoot  string
 	Context      Context
+	User         *User
 	Params       Params
 	CGIData      CGIData
 	URL          string
+	Component    string
+	Action       string
 	Fingerprint  string
+	BodyParams   map[string]interface{}
+	Session      Session
+	Cookies      map[string]string
+	Revision     string
+	Build        BuildInfo
+
+	filter *keyFilter
+	stats  *hash
+	limits payloadLimits
 }
 
 func (n *Notice) asJSON() *hash {
-	return &hash{
+	payload := hash{
 		"api_key": n.APIKey,
 		"notifier": &hash{
 			"name":    "honeybadger",
@@ -64,13 +89,18 @@ func (n *Notice) asJSON() *hash {
 			"class":       n.ErrorClass,
 			"tags":        n.Tags,
 			"backtrace":   n.Backtrace,
+			"causes":      n.Causes,
 			"fingerprint": n.Fingerprint,
 		},
 		"request": &hash{
-			"context":  n.Context,
-			"params":   n.Params,
-			"cgi_data": n.CGIData,
-			"url":      n.URL,
+			"context":   userData{n.filter.value(n.context())},
+			"params":    userData{n.params()},
+			"cgi_data":  userData{n.filter.value(n.CGIData)},
+			"session":   userData{n.filter.value(n.Session)},
+			"cookies":   userData{n.filter.value(n.Cookies)},
+			"url":       n.filter.url(n.URL),
+			"component": n.Component,
+			"action":    n.Action,
 		},
 		"server": &hash{
 			"project_root":     n.ProjectRoot,
@@ -78,72 +108,130 @@ func (n *Notice) asJSON() *hash {
 			"hostname":         n.Hostname,
 			"time":             time.Now().UTC(),
 			"pid":              os.Getpid(),
-			"stats":            getStats(),
+			"revision":         n.Revision,
+			"build":            n.Build,
+			"stats":            n.stats,
 		},
 	}
-}
 
-func bytesToKB(bytes uint64) float64 {
-	return float64(bytes) / 1024.0
-}
+	if len(n.Breadcrumbs) > 0 {
+		trail := make([]hash, len(n.Breadcrumbs))
+		for i, b := range n.Breadcrumbs {
+			trail[i] = hash{
+				"message":   b.Message,
+				"category":  b.Category,
+				"metadata":  userData{b.Metadata},
+				"timestamp": b.Timestamp,
+			}
+		}
+		payload["breadcrumbs"] = &hash{
+			"enabled": true,
+			"trail":   trail,
+		}
+	}
+
+	if len(n.Goroutines) > 0 {
+		payload["details"] = &hash{"goroutines": n.Goroutines}
+	}
 
-func getStats() *hash {
-	var m, l *hash
+	return &payload
+}
 
-	if stat, err := mem.VirtualMemory(); err == nil {
-		m = &hash{
-			"total":      bytesToKB(stat.Total),
-			"free":       bytesToKB(stat.Free),
-			"buffers":    bytesToKB(stat.Buffers),
-			"cached":     bytesToKB(stat.Cached),
-			"free_total": bytesToKB(stat.Free + stat.Buffers + stat.Cached),
-		}
+// params returns the filtered request params, including those parsed from a
+// JSON request body.
+func (n *Notice) params() interface{} {
+	if len(n.BodyParams) == 0 {
+		return n.filter.value(n.Params)
 	}
 
-	if stat, err := load.Avg(); err == nil {
-		l = &hash{
-			"one":     stat.Load1,
-			"five":    stat.Load5,
-			"fifteen": stat.Load15,
-		}
+	params := make(map[string]interface{}, len(n.Params)+len(n.BodyParams))
+	for k, v := range n.Params {
+		params[k] = v
+	}
+	for k, v := range n.BodyParams {
+		params[k] = v
 	}
+	return n.filter.value(params)
+}
 
-	return &hash{"mem": m, "load": l}
+func bytesToKB(bytes uint64) float64 {
+	return float64(bytes) / 1024.0
 }
 
 func (n *Notice) toJSON() []byte {
-	out, err := json.Marshal(n.asJSON())
-	if err == nil {
-		return out
-	}
-	panic(err)
+	return marshalPayload(n.asJSON(), n.limits)
 }
 
 func (n *Notice) setContext(context Context) {
 	n.Context.Update(context)
 }
 
-func composeStack(stack []*Frame, root string) (frames []*Frame) {
-	if root == "" {
-		return stack
+// context returns the notice context including the keys of the user.
+func (n *Notice) context() Context {
+	if n.User == nil {
+		return n.Context
 	}
 
-	re, err := regexp.Compile("^" + regexp.QuoteMeta(root))
-	if err != nil {
-		return stack
+	context := make(Context, len(n.Context))
+	context.Update(n.Context)
+	context.Update(n.User.Context())
+	return context
+}
+
+func (n *Notice) setSession(session Session) {
+	if n.Session == nil {
+		n.Session = Session{}
 	}
+	for k, v := range session {
+		n.Session[k] = v
+	}
+}
 
+func composeStack(stack []*Frame, config *Configuration) (frames []*Frame) {
 	for _, frame := range stack {
-		file := re.ReplaceAllString(frame.File, "[PROJECT_ROOT]")
-		frames = append(frames, &Frame{
-			File:   file,
-			Number: frame.Number,
-			Method: frame.Method,
-		})
+		if config.MaxFrames > 0 && len(frames) >= config.MaxFrames {
+			break
+		}
+
+		file, app := normalizeFramePath(frame.File, config.Root)
+
+		composed := &Frame{
+			File:    file,
+			Number:  frame.Number,
+			Method:  frame.Method,
+			Context: FrameContextAll,
+		}
+		if app {
+			composed.Context = FrameContextApp
+		}
+
+		if config.FrameFilter != nil {
+			if composed = config.FrameFilter(composed); composed == nil {
+				continue
+			}
+		}
+
+		if composed.Context == FrameContextApp {
+			composed.Source = sourceContext(composed.File, composed.Number, config.Root, config.SourceContextLines)
+		}
+
+		frames = append(frames, composed)
 	}
 	return
 }
 
+func composeCauses(causes []*Cause, config *Configuration) []*Cause {
+	result := make([]*Cause, 0, len(causes))
+	for _, cause := range causes {
+		result = append(result, &Cause{
+			Class:     cause.Class,
+			Message:   cause.Message,
+			Backtrace: composeStack(cause.Backtrace, config),
+		})
+	}
+	return result
+}
+
 func newNotice(config *Configuration, err Error, extra ...interface{}) *Notice {
 	notice := Notice{
 		APIKey:       config.APIKey,
@@ -153,17 +241,29 @@ func newNotice(config *Configuration, err Error, extra ...interface{}) *Notice {
 		ErrorClass:   err.Class,
 		Env:          config.Env,
 		Hostname:     config.Hostname,
-		Backtrace:    composeStack(err.Stack, config.Root),
+		Backtrace:    composeStack(err.Stack, config),
+		Causes:       composeCauses(err.Causes, config),
 		ProjectRoot:  config.Root,
 		Context:      Context{},
+		Revision:     revision(config),
+		Build:        readBuildInfo(),
+		filter:       newKeyFilter(config.FilterKeys),
 	}
 
+	setErrorMetadata(&notice, err.err)
+
 	for _, thing := range extra {
 		switch t := thing.(type) {
 		case Context:
 			notice.setContext(t)
 		case ErrorClass:
 			notice.ErrorClass = t.Name
+		case User:
+			notice.User = &t
+		case Component:
+			notice.Component = t.Name
+		case Action:
+			notice.Action = t.Name
 		case Tags:
 			for _, tag := range t {
 				notice.Tags = append(notice.Tags, tag)
@@ -174,22 +274,119 @@ func newNotice(config *Configuration, err Error, extra ...interface{}) *Notice {
 			notice.Params = t
 		case CGIData:
 			notice.CGIData = t
+		case Session:
+			notice.setSession(t)
 		case url.URL:
 			notice.URL = t.String()
 		case *http.Request:
-			setHttpRequest(&notice, t)
+			setHttpRequest(&notice, t, config)
+		case goroutineDump:
+			notice.Goroutines = composeGoroutines(t, config)
+		case breadcrumbTrail:
+			notice.Breadcrumbs = mergeBreadcrumbs(config.MaxBreadcrumbs, notice.Breadcrumbs, t)
+		case context.Context:
+			scope := noticeScopeFromContext(t)
+			notice.setContext(scope.context)
+			notice.Tags = append(notice.Tags, scope.tags...)
+			if scope.fingerprint != "" {
+				notice.Fingerprint = scope.fingerprint
+			}
+			if scope.user != nil {
+				notice.User = scope.user
+			}
+			if trail := breadcrumbsFromContext(t); trail != nil {
+				notice.Breadcrumbs = mergeBreadcrumbs(config.MaxBreadcrumbs, notice.Breadcrumbs, trail.all())
+			}
 		}
 	}
 
 	return &notice
 }
 
-func setHttpRequest(notice *Notice, r *http.Request) {
+// setErrorMetadata applies the metadata provided by the errors in the chain of
+// err. Extras passed to newNotice are applied afterwards and take precedence.
+func setErrorMetadata(notice *Notice, err error) {
+	errs := flattenErrors(err)
+
+	for i := len(errs) - 1; i >= 0; i-- {
+		if p, ok := errs[i].(ContextProvider); ok {
+			notice.setContext(p.HoneybadgerContext())
+		}
+	}
+
+	for _, e := range errs {
+		if p, ok := e.(TagsProvider); ok {
+			for _, tag := range p.HoneybadgerTags() {
+				if !slices.Contains(notice.Tags, tag) {
+					notice.Tags = append(notice.Tags, tag)
+				}
+			}
+		}
+	}
+
+	for _, e := range errs {
+		if p, ok := e.(FingerprintProvider); ok {
+			if fingerprint := p.HoneybadgerFingerprint(); fingerprint != "" {
+				notice.Fingerprint = fingerprint
+				break
+			}
+		}
+	}
+
+	for _, e := range errs {
+		if p, ok := e.(ClassProvider); ok {
+			if class := p.HoneybadgerClass(); class != "" {
+				notice.ErrorClass = class
+				break
+			}
+		}
+	}
+}
+
+// DefaultRequestRoute is the default Configuration.RequestRoute. It returns
+// the path of the http.ServeMux pattern which matched r as the component, and
+// the method of the pattern or request as the action. For example, a request
+// matched by "GET /users/{id}" has the component "/users/{id}" and the action
+// "GET".
+func DefaultRequestRoute(r *http.Request) (component, action string) {
+	pattern := r.Pattern
+	if pattern == "" {
+		return "", ""
+	}
+
+	action = r.Method
+	if method, path, ok := strings.Cut(pattern, " "); ok {
+		action, pattern = method, strings.TrimLeft(path, " \t")
+	}
+
+	// Patterns may start with a host, such as "example.com/users/".
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+
+	return pattern, action
+}
+
+func setHttpRequest(notice *Notice, r *http.Request, config *Configuration) {
 	if r == nil {
 		return
 	}
 
 	notice.URL = r.URL.String()
+
+	// Component and Action passed explicitly take precedence over the route.
+	resolveRoute := config.RequestRoute
+	if resolveRoute == nil {
+		resolveRoute = DefaultRequestRoute
+	}
+	component, action := resolveRoute(r)
+	if notice.Component == "" {
+		notice.Component = component
+	}
+	if notice.Action == "" {
+		notice.Action = action
+	}
+
 	notice.CGIData = CGIData{}
 
 	replacer := strings.NewReplacer("-", "_")
@@ -198,12 +395,25 @@ func setHttpRequest(notice *Notice, r *http.Request) {
 		notice.CGIData[key] = v[0]
 	}
 
+	if cookies := r.Cookies(); len(cookies) > 0 {
+		notice.Cookies = make(map[string]string, len(cookies))
+		for _, cookie := range cookies {
+			notice.Cookies[cookie.Name] = cookie.Value
+		}
+	}
+
 	// Form is only populated if ParseForm() is called on the request and it
 	// will include URL query parameters. So if it's empty, then it's possible
 	// that ParseForm wasn't called, and we will miss reporting URL params.
-	if form := r.Form; len(form) == 0 {
+	form := r.Form
+	if len(form) == 0 {
 		notice.Params = Params(r.URL.Query())
 	} else {
 		notice.Params = Params(form)
 	}
+
+	// A parsed form already includes the values of a form body.
+	if body, ok := r.Body.(*capturedBody); ok && (len(form) == 0 || isJSONMediaType(body.mediaType)) {
+		setRequestBody(notice, body)
+	}
 }
@@ -3,6 +3,8 @@ package honeybadger
 import (
 	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -72,3 +74,143 @@ func TestToJSON(t *testing.T) {
 
 	testNoticePayload(t, payload)
 }
+
+func TestNoticeCauses(t *testing.T) {
+	err := newTestError()
+	err.Causes = []*Cause{{
+		Class:     "*errors.errorString",
+		Message:   "Cobras!",
+		Backtrace: []*Frame{{File: "/path/to/root/cobras.go", Number: "3", Method: "cobras"}},
+	}}
+
+	notice := newNotice(&Configuration{Root: "/path/to/root"}, err)
+
+	if len(notice.Causes) != 1 {
+		t.Fatalf("Expected notice to include causes. actual=%#v", notice.Causes)
+	}
+
+	if file := notice.Causes[0].Backtrace[0].File; file != "[PROJECT_ROOT]/cobras.go" {
+		t.Errorf("Expected cause backtrace to substitute project root. expected=%#v result=%#v", "[PROJECT_ROOT]/cobras.go", file)
+	}
+
+	var payload hash
+	if err := json.Unmarshal(notice.toJSON(), &payload); err != nil {
+		t.Fatalf("Got error while parsing notice JSON err=%#v", err)
+	}
+
+	errorPayload, _ := payload["error"].(map[string]any)
+	causes, _ := errorPayload["causes"].([]any)
+	if len(causes) != 1 {
+		t.Fatalf("Expected causes in payload. actual=%#v", errorPayload["causes"])
+	}
+
+	cause, _ := causes[0].(map[string]any)
+	if cause["class"] != "*errors.errorString" || cause["message"] != "Cobras!" {
+		t.Errorf("Unexpected cause in payload. actual=%#v", cause)
+	}
+}
+
+func TestNoticeSessionAndCookies(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	req.AddCookie(&http.Cookie{Name: "auth_token", Value: "abc"})
+
+	notice := newNotice(newConfig(Configuration{}), newTestError(), req,
+		Session{"user_id": 1},
+		Session{"secret": "honey"},
+	)
+
+	if notice.Cookies["theme"] != "dark" || notice.Cookies["auth_token"] != "abc" {
+		t.Errorf("Expected cookies to be parsed. actual=%#v", notice.Cookies)
+	}
+
+	var payload hash
+	if err := json.Unmarshal(notice.toJSON(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	request := payload["request"].(map[string]any)
+
+	session := request["session"].(map[string]any)
+	if session["user_id"] != float64(1) || session["secret"] != filteredValue {
+		t.Errorf("Expected filtered session. actual=%#v", session)
+	}
+
+	cookies := request["cookies"].(map[string]any)
+	if cookies["theme"] != "dark" || cookies["auth_token"] != filteredValue {
+		t.Errorf("Expected filtered cookies. actual=%#v", cookies)
+	}
+
+	if cgi := request["cgi_data"].(map[string]any); cgi["HTTP_COOKIE"] != filteredValue {
+		t.Errorf("Expected raw cookie header to be filtered. actual=%#v", cgi)
+	}
+}
+
+func TestDefaultRequestRoute(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		method    string
+		component string
+		action    string
+	}{
+		{"GET /users/{id}", "GET", "/users/{id}", "GET"},
+		{"/users/", "POST", "/users/", "POST"},
+		{"example.com/users/{id}", "PUT", "/users/{id}", "PUT"},
+		{"", "GET", "", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/users/1", nil)
+		req.Pattern = tt.pattern
+
+		component, action := DefaultRequestRoute(req)
+		if component != tt.component || action != tt.action {
+			t.Errorf("DefaultRequestRoute(%q) expected=(%q, %q) actual=(%q, %q)", tt.pattern, tt.component, tt.action, component, action)
+		}
+	}
+}
+
+func TestNoticeComponentAndAction(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		panic("Cobras!")
+	})
+
+	func() {
+		defer func() { _ = recover() }()
+		client.Handler(mux).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+	}()
+
+	notices := backend.GetNotices()
+	if len(notices) != 1 {
+		t.Fatalf("Expected 1 notice. actual=%d", len(notices))
+	}
+	if notices[0].Component != "/users/{id}" || notices[0].Action != "GET" {
+		t.Errorf("Expected component and action from route. actual=(%q, %q)", notices[0].Component, notices[0].Action)
+	}
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Pattern = "GET /users/{id}"
+	notice := newNotice(&Configuration{}, newTestError(), Component{"users"}, req, Action{"show"})
+	if notice.Component != "users" || notice.Action != "show" {
+		t.Errorf("Expected explicit component and action to take precedence. actual=(%q, %q)", notice.Component, notice.Action)
+	}
+
+	config := &Configuration{RequestRoute: func(r *http.Request) (string, string) {
+		return "users", "show"
+	}}
+	notice = newNotice(config, newTestError(), httptest.NewRequest("GET", "/users/1", nil))
+	if notice.Component != "users" || notice.Action != "show" {
+		t.Errorf("Expected component and action from RequestRoute. actual=(%q, %q)", notice.Component, notice.Action)
+	}
+
+	var payload hash
+	if err := json.Unmarshal(notice.toJSON(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if request := payload["request"].(map[string]any); request["component"] != "users" || request["action"] != "show" {
+		t.Errorf("Expected component and action in payload. actual=%#v", request)
+	}
+}
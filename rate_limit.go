@@ -0,0 +1,44 @@
+package honeybadger
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by Notify when a notice is dropped because
+// Configuration.RateLimit was exceeded.
+var ErrRateLimited = errors.New("notice dropped by rate limit")
+
+// rateLimiter is a token bucket holding up to limit tokens, which refills at
+// limit tokens per interval.
+type rateLimiter struct {
+	sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token from the bucket and reports whether one was available.
+// A limit or interval of 0 allows everything.
+func (l *rateLimiter) allow(limit int, interval time.Duration, now time.Time) bool {
+	if limit <= 0 || interval <= 0 {
+		return true
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	if l.last.IsZero() {
+		l.tokens = float64(limit)
+	} else {
+		refill := float64(now.Sub(l.last)) / float64(interval) * float64(limit)
+		l.tokens = min(float64(limit), l.tokens+refill)
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
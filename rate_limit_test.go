@@ -0,0 +1,51 @@
+package honeybadger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := &rateLimiter{}
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if !limiter.allow(2, time.Minute, now) {
+			t.Fatalf("Expected burst of limit to be allowed. i=%d", i)
+		}
+	}
+	if limiter.allow(2, time.Minute, now) {
+		t.Errorf("Expected bucket to be empty.")
+	}
+	if !limiter.allow(2, time.Minute, now.Add(30*time.Second)) {
+		t.Errorf("Expected bucket to refill.")
+	}
+	if limiter.allow(2, time.Minute, now.Add(30*time.Second)) {
+		t.Errorf("Expected bucket to refill at limit per interval.")
+	}
+	if !limiter.allow(0, time.Minute, now) {
+		t.Errorf("Expected limit of 0 to allow everything.")
+	}
+}
+
+func TestNotifyRateLimit(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, RateLimit: 2})
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Notify("Cobras!")
+		if i < 2 && err != nil {
+			t.Errorf("Expected notice to be sent. i=%d err=%v", i, err)
+		}
+		if i == 2 && err != ErrRateLimited {
+			t.Errorf("Expected notice to be rate limited. err=%v", err)
+		}
+	}
+
+	if notices := backend.GetNotices(); len(notices) != 2 {
+		t.Errorf("Expected rate limited notice to be dropped. actual=%d", len(notices))
+	}
+	if stats := client.Stats(); stats.RateLimited != 1 {
+		t.Errorf("Expected rate limited count. expected=%#v actual=%#v", 1, stats.RateLimited)
+	}
+}
@@ -0,0 +1,109 @@
+package honeybadger
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// capturedBody replaces the body of a request captured by Client.Handler. It
+// copies up to limit bytes of what the handler reads into body, so capturing
+// never reads more of the body than the handler does. Reads, including their
+// errors, are passed through unchanged.
+type capturedBody struct {
+	io.ReadCloser
+
+	body      []byte
+	limit     int
+	mediaType string
+	truncated bool
+	complete  bool
+}
+
+// captureRequestBody replaces the body of r with a capturedBody when it has a
+// JSON or form content type.
+func captureRequestBody(r *http.Request, limit int) {
+	if r.Body == nil || r.Body == http.NoBody || limit <= 0 {
+		return
+	}
+
+	mediaType := requestMediaType(r)
+	if !isJSONMediaType(mediaType) && mediaType != "application/x-www-form-urlencoded" {
+		return
+	}
+
+	r.Body = &capturedBody{
+		ReadCloser: r.Body,
+		limit:      limit,
+		mediaType:  mediaType,
+	}
+}
+
+func (b *capturedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if room := b.limit - len(b.body); room < n {
+			b.body = append(b.body, p[:max(room, 0)]...)
+			b.truncated = true
+		} else {
+			b.body = append(b.body, p[:n]...)
+		}
+	}
+	if err == io.EOF {
+		b.complete = true
+	}
+	return n, err
+}
+
+func requestMediaType(r *http.Request) string {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// setRequestBody adds the parameters of a captured body to notice. Bodies
+// which were truncated, or not read to the end by the handler, are skipped,
+// since they can't be parsed reliably.
+func setRequestBody(notice *Notice, body *capturedBody) {
+	if body.truncated || !body.complete || len(body.body) == 0 {
+		return
+	}
+
+	if isJSONMediaType(body.mediaType) {
+		var parsed interface{}
+		if err := json.Unmarshal(body.body, &parsed); err != nil {
+			return
+		}
+
+		// Bodies which aren't JSON objects are reported under "_json".
+		if object, ok := parsed.(map[string]interface{}); ok {
+			notice.BodyParams = object
+		} else {
+			notice.BodyParams = map[string]interface{}{"_json": parsed}
+		}
+		return
+	}
+
+	values, err := url.ParseQuery(string(body.body))
+	if err != nil {
+		return
+	}
+
+	params := make(Params, len(notice.Params)+len(values))
+	for k, v := range notice.Params {
+		params[k] = v
+	}
+	for k, v := range values {
+		params[k] = append(params[k], v...)
+	}
+	notice.Params = params
+}
@@ -0,0 +1,187 @@
+package honeybadger
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCaptureRequestBody(t *testing.T) {
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"badger"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	captureRequestBody(req, 1024)
+
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != `{"name":"badger"}` {
+		t.Errorf("Expected handler to read the full body. actual=%q", body)
+	}
+
+	captured, ok := req.Body.(*capturedBody)
+	if !ok || string(captured.body) != `{"name":"badger"}` || captured.truncated || !captured.complete {
+		t.Errorf("Expected body to be captured. actual=%#v", req.Body)
+	}
+}
+
+func TestCaptureRequestBodyNotRead(t *testing.T) {
+	body := &countingReader{Reader: strings.NewReader(`{"name":"badger"}`)}
+	req := httptest.NewRequest("POST", "/users", body)
+	req.Header.Set("Content-Type", "application/json")
+
+	captureRequestBody(req, 1024)
+
+	if body.reads != 0 {
+		t.Errorf("Expected body not to be read before the handler. reads=%d", body.reads)
+	}
+
+	// A partially read body isn't reported.
+	req.Body.Read(make([]byte, 4))
+	notice := newNotice(&Configuration{}, newTestError(), req)
+	if notice.BodyParams != nil {
+		t.Errorf("Expected partial body not to be parsed. actual=%#v", notice.BodyParams)
+	}
+}
+
+func TestCaptureRequestBodyReadError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	req := httptest.NewRequest("POST", "/users", io.MultiReader(strings.NewReader(`{"na`), iotest.ErrReader(readErr)))
+	req.Header.Set("Content-Type", "application/json")
+
+	captureRequestBody(req, 1024)
+
+	body, err := io.ReadAll(req.Body)
+	if err != readErr || string(body) != `{"na` {
+		t.Errorf("Expected read error to be passed through. body=%q err=%v", body, err)
+	}
+
+	notice := newNotice(&Configuration{}, newTestError(), req)
+	if notice.BodyParams != nil {
+		t.Errorf("Expected failed body not to be parsed. actual=%#v", notice.BodyParams)
+	}
+}
+
+type countingReader struct {
+	io.Reader
+	reads int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.Reader.Read(p)
+}
+
+func TestCaptureRequestBodyTruncated(t *testing.T) {
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"badger"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	captureRequestBody(req, 4)
+
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != `{"name":"badger"}` {
+		t.Errorf("Expected handler to read the full body. actual=%q", body)
+	}
+
+	captured := req.Body.(*capturedBody)
+	if string(captured.body) != `{"na` || !captured.truncated {
+		t.Errorf("Expected body to be truncated. actual=%#v", captured)
+	}
+
+	notice := newNotice(&Configuration{}, newTestError(), req)
+	if notice.BodyParams != nil {
+		t.Errorf("Expected truncated body not to be parsed. actual=%#v", notice.BodyParams)
+	}
+}
+
+func TestCaptureRequestBodyContentType(t *testing.T) {
+	req := httptest.NewRequest("POST", "/upload", strings.NewReader("binary"))
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	captureRequestBody(req, 1024)
+
+	if _, ok := req.Body.(*capturedBody); ok {
+		t.Errorf("Expected body with other content type not to be captured.")
+	}
+}
+
+func TestNoticeRequestBodyParams(t *testing.T) {
+	req := httptest.NewRequest("POST", "/users?page=1", strings.NewReader(`{"name":"badger","password":"honey"}`))
+	req.Header.Set("Content-Type", "application/json")
+	captureRequestBody(req, 1024)
+	io.ReadAll(req.Body)
+
+	notice := newNotice(newConfig(Configuration{}), newTestError(), req)
+
+	var payload hash
+	if err := json.Unmarshal(notice.toJSON(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	params := payload["request"].(map[string]any)["params"].(map[string]any)
+	if params["name"] != "badger" || params["password"] != filteredValue || params["page"].([]any)[0] != "1" {
+		t.Errorf("Expected JSON body in params. actual=%#v", params)
+	}
+
+	req = httptest.NewRequest("POST", "/users", strings.NewReader(`[1,2]`))
+	req.Header.Set("Content-Type", "application/vnd.api+json")
+	captureRequestBody(req, 1024)
+	io.ReadAll(req.Body)
+
+	notice = newNotice(&Configuration{}, newTestError(), req)
+	if list, _ := notice.BodyParams["_json"].([]any); len(list) != 2 {
+		t.Errorf("Expected JSON array under _json. actual=%#v", notice.BodyParams)
+	}
+}
+
+func TestNoticeRequestFormBody(t *testing.T) {
+	req := httptest.NewRequest("POST", "/users?page=1", strings.NewReader("name=badger"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	captureRequestBody(req, 1024)
+	io.ReadAll(req.Body)
+
+	notice := newNotice(&Configuration{}, newTestError(), req)
+	if notice.Params["name"][0] != "badger" || notice.Params["page"][0] != "1" {
+		t.Errorf("Expected form body in params. actual=%#v", notice.Params)
+	}
+
+	req = httptest.NewRequest("POST", "/users?page=1", strings.NewReader("name=badger"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	captureRequestBody(req, 1024)
+	req.ParseForm()
+	notice = newNotice(&Configuration{}, newTestError(), req)
+	if len(notice.Params["name"]) != 1 {
+		t.Errorf("Expected parsed form not to be duplicated. actual=%#v", notice.Params)
+	}
+}
+
+func TestHandlerCapturesRequestBody(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, CaptureRequestBody: true})
+
+	var read string
+	handler := client.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		read = string(body)
+		panic("Cobras!")
+	}))
+
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"badger"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	func() {
+		defer func() { _ = recover() }()
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	if read != `{"name":"badger"}` {
+		t.Errorf("Expected handler to read the body. actual=%q", read)
+	}
+
+	notices := backend.GetNotices()
+	if len(notices) != 1 || notices[0].BodyParams["name"] != "badger" {
+		t.Errorf("Expected notice to include body params. actual=%#v", notices)
+	}
+}
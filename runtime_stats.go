@@ -0,0 +1,87 @@
+package honeybadger
+
+import (
+	"math"
+	"runtime/metrics"
+	"time"
+)
+
+// processStart approximates the start time of the process, for uptime.
+var processStart = time.Now()
+
+// The runtime/metrics samples read by runtimeStats.
+const (
+	metricGoroutines  = "/sched/goroutines:goroutines"
+	metricHeapObjects = "/memory/classes/heap/objects:bytes"
+	metricHeapUnused  = "/memory/classes/heap/unused:bytes"
+	metricGCCycles    = "/gc/cycles/total:gc-cycles"
+	metricGCPauses    = "/sched/pauses/total/gc:seconds"
+	metricGOMAXPROCS  = "/sched/gomaxprocs:threads"
+)
+
+var runtimeMetrics = []string{
+	metricGoroutines,
+	metricHeapObjects,
+	metricHeapUnused,
+	metricGCCycles,
+	metricGCPauses,
+	metricGOMAXPROCS,
+}
+
+// runtimeStats returns statistics of the Go runtime. Heap sizes are in KB,
+// the GC pause total is in milliseconds, estimated from the pause histogram,
+// and the uptime is in seconds.
+func runtimeStats() *hash {
+	samples := make([]metrics.Sample, len(runtimeMetrics))
+	for i, name := range runtimeMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	stats := hash{"uptime": time.Since(processStart).Seconds()}
+	values := make(map[string]metrics.Value, len(samples))
+	for _, sample := range samples {
+		values[sample.Name] = sample.Value
+	}
+
+	if v := values[metricGoroutines]; v.Kind() == metrics.KindUint64 {
+		stats["goroutines"] = v.Uint64()
+	}
+	if objects, unused := values[metricHeapObjects], values[metricHeapUnused]; objects.Kind() == metrics.KindUint64 {
+		stats["heap_alloc"] = bytesToKB(objects.Uint64())
+		if unused.Kind() == metrics.KindUint64 {
+			stats["heap_inuse"] = bytesToKB(objects.Uint64() + unused.Uint64())
+		}
+	}
+	if v := values[metricGCCycles]; v.Kind() == metrics.KindUint64 {
+		stats["gc_count"] = v.Uint64()
+	}
+	if v := values[metricGCPauses]; v.Kind() == metrics.KindFloat64Histogram {
+		stats["gc_pause_total"] = histogramTotal(v.Float64Histogram()) * 1000
+	}
+	if v := values[metricGOMAXPROCS]; v.Kind() == metrics.KindUint64 {
+		stats["gomaxprocs"] = v.Uint64()
+	}
+
+	return &stats
+}
+
+// histogramTotal estimates the sum of the values in h from the midpoints of
+// its buckets. Unbounded buckets use their finite boundary.
+func histogramTotal(h *metrics.Float64Histogram) float64 {
+	var total float64
+	for i, count := range h.Counts {
+		if count == 0 {
+			continue
+		}
+		low, high := h.Buckets[i], h.Buckets[i+1]
+		value := (low + high) / 2
+		if math.IsInf(low, -1) {
+			value = high
+		} else if math.IsInf(high, 1) {
+			value = low
+		}
+		total += value * float64(count)
+	}
+	return total
+}
@@ -0,0 +1,37 @@
+package honeybadger
+
+import (
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"testing"
+)
+
+func TestRuntimeStats(t *testing.T) {
+	runtime.GC()
+	stats := *runtimeStats()
+
+	for _, key := range []string{"goroutines", "heap_alloc", "heap_inuse", "gc_count", "gc_pause_total", "gomaxprocs", "uptime"} {
+		if _, ok := stats[key]; !ok {
+			t.Errorf("Expected runtime stat. key=%#v stats=%#v", key, stats)
+		}
+	}
+	if stats["gomaxprocs"] != uint64(runtime.GOMAXPROCS(0)) {
+		t.Errorf("Unexpected gomaxprocs. expected=%#v actual=%#v", runtime.GOMAXPROCS(0), stats["gomaxprocs"])
+	}
+	if count, _ := stats["gc_count"].(uint64); count == 0 {
+		t.Errorf("Expected GC count after runtime.GC. actual=%#v", stats["gc_count"])
+	}
+}
+
+func TestHistogramTotal(t *testing.T) {
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 2, 1},
+		Buckets: []float64{math.Inf(-1), 1, 3, math.Inf(1)},
+	}
+
+	// 1 + 2*2 + 3
+	if total := histogramTotal(h); total != 8 {
+		t.Errorf("Unexpected histogram total. expected=%#v actual=%#v", 8.0, total)
+	}
+}
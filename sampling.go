@@ -0,0 +1,49 @@
+package honeybadger
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// sampleRateKey is the context key holding the rate a notice was sampled at.
+const sampleRateKey = "sample_rate"
+
+// SampleRate reports only a fraction of the notices it matches. A notice
+// matches when its class is Class, it has the tag Tag, or Match returns true.
+// Rate is the fraction reported, between 0 and 1.
+type SampleRate struct {
+	Class string
+	Tag   string
+	Match func(*Notice) bool
+	Rate  float64
+}
+
+func (s SampleRate) matches(notice *Notice) bool {
+	if s.Class != "" && s.Class == notice.ErrorClass {
+		return true
+	}
+	if s.Tag != "" && slices.Contains(notice.Tags, s.Tag) {
+		return true
+	}
+	return s.Match != nil && s.Match(notice)
+}
+
+// sampleNotice applies the first of rates matching notice, and reports
+// whether the notice was sampled out. The rate of sampled notices is recorded
+// in their context, so that counts can be extrapolated.
+func sampleNotice(notice *Notice, rates []SampleRate) bool {
+	for _, rate := range rates {
+		if !rate.matches(notice) {
+			continue
+		}
+		if rand.Float64() >= rate.Rate {
+			return true
+		}
+		if notice.Context == nil {
+			notice.Context = Context{}
+		}
+		notice.Context[sampleRateKey] = rate.Rate
+		return false
+	}
+	return false
+}
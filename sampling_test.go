@@ -0,0 +1,50 @@
+package honeybadger
+
+import "testing"
+
+func TestSampleRateMatches(t *testing.T) {
+	notice := &Notice{ErrorClass: "*errors.errorString", Tags: []string{"cache"}}
+
+	for _, rate := range []SampleRate{
+		{Class: "*errors.errorString"},
+		{Tag: "cache"},
+		{Match: func(n *Notice) bool { return n.ErrorClass == "*errors.errorString" }},
+	} {
+		if !rate.matches(notice) {
+			t.Errorf("Expected sample rate to match. rate=%#v", rate)
+		}
+	}
+
+	if (SampleRate{Class: "other", Tag: "other"}).matches(notice) {
+		t.Errorf("Expected sample rate not to match.")
+	}
+}
+
+func TestNotifySampleRates(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true, SampleRates: []SampleRate{
+		{Tag: "never", Rate: 0},
+		{Tag: "always", Rate: 1},
+	}})
+
+	token, err := client.Notify("Cobras!", Tags{"never"})
+	if token != "" || err != nil {
+		t.Errorf("Expected sampled out notice to be skipped. token=%#v err=%v", token, err)
+	}
+	client.Notify("Cobras!", Tags{"always"})
+	client.Notify("Cobras!")
+
+	notices := backend.GetNotices()
+	if len(notices) != 2 {
+		t.Fatalf("Expected sampled notices to be sent. actual=%d", len(notices))
+	}
+	if rate := notices[0].Context[sampleRateKey]; rate != 1.0 {
+		t.Errorf("Expected sample rate in context. expected=%#v actual=%#v", 1.0, rate)
+	}
+	if _, ok := notices[1].Context[sampleRateKey]; ok {
+		t.Errorf("Expected no sample rate for unmatched notice. context=%#v", notices[1].Context)
+	}
+	if stats := client.Stats(); stats.Sampled != 1 {
+		t.Errorf("Expected sampled count. expected=%#v actual=%#v", 1, stats.Sampled)
+	}
+}
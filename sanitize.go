@@ -0,0 +1,386 @@
+package honeybadger
+
+import (
+	"encoding"
+	"encoding/json"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"unsafe"
+)
+
+// Placeholders replacing the values of payloads which can't be serialized.
+const (
+	cyclePlaceholder    = "[cycle]"
+	maxDepthPlaceholder = "[max depth]"
+)
+
+// maxPayloadDepth is the maximum nesting of maps, slices and structs
+// serialized in payloads which aren't limited by a client's configuration.
+const maxPayloadDepth = 32
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	errorValueType    = reflect.TypeFor[error]()
+)
+
+// sanitizer converts values to the types understood by encoding/json,
+// replacing values which can't be serialized, such as channels, functions
+// and cycles, with placeholders, and truncating the user data which exceeds
+// its limits.
+type sanitizer struct {
+	limits    payloadLimits
+	active    payloadLimits
+	visiting  map[visit]bool
+	truncated bool
+}
+
+// userData marks a subtree of a payload supplied by the user, such as the
+// context of a notice. The payload limits only apply to user data, with the
+// depth measured from the root of each subtree, so that the fields of the
+// payload itself are never truncated.
+type userData struct {
+	value interface{}
+}
+
+var userDataType = reflect.TypeFor[userData]()
+
+type visit struct {
+	ptr unsafe.Pointer
+	typ reflect.Type
+}
+
+func newSanitizer(limits payloadLimits) *sanitizer {
+	return &sanitizer{
+		limits:   limits,
+		active:   defaultPayloadLimits,
+		visiting: make(map[visit]bool),
+	}
+}
+
+// marshalPayload serializes payload as JSON after sanitizing it, so that it
+// never fails. The limits apply to the userData of payload. Payloads larger
+// than limits.maxBytes are serialized again with smaller string and
+// collection limits until they fit, or the limits can't shrink any further.
+func marshalPayload(payload interface{}, limits payloadLimits) []byte {
+	truncated := false
+	for {
+		s := newSanitizer(limits)
+		out, err := json.Marshal(s.sanitize(payload))
+		if err != nil {
+			out, _ = json.Marshal(unserializable(reflect.TypeOf(payload)))
+		}
+		truncated = truncated || s.truncated
+
+		if limits.maxBytes <= 0 || len(out) <= limits.maxBytes || !limits.shrink() {
+			if truncated && limits.truncated != nil {
+				limits.truncated.Add(1)
+			}
+			return out
+		}
+		truncated = true
+	}
+}
+
+func unserializable(t reflect.Type) string {
+	return "[unserializable " + t.String() + "]"
+}
+
+func (s *sanitizer) sanitize(value interface{}) interface{} {
+	return s.value(reflect.ValueOf(value), 0)
+}
+
+func (s *sanitizer) value(v reflect.Value, depth int) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+	}
+
+	t := v.Type()
+	switch {
+	case t == userDataType:
+		return s.userData(v.Interface().(userData))
+	case t.Implements(jsonMarshalerType):
+		return s.marshalJSON(v)
+	case t.Implements(textMarshalerType):
+		return s.marshalText(v)
+	case t.Implements(errorValueType) && v.Kind() != reflect.Interface:
+		return s.errorMessage(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return f
+		}
+		return unserializable(t)
+	case reflect.String:
+		return s.truncateString(v.String())
+	case reflect.Interface:
+		return s.value(v.Elem(), depth)
+	case reflect.Pointer:
+		return s.visit(v, func() interface{} {
+			return s.value(v.Elem(), depth)
+		})
+	case reflect.Map:
+		if s.tooDeep(depth) {
+			return maxDepthPlaceholder
+		}
+		return s.visit(v, func() interface{} {
+			return s.mapValue(v, depth)
+		})
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return s.truncateBytes(v.Bytes())
+		}
+		if s.tooDeep(depth) {
+			return maxDepthPlaceholder
+		}
+		return s.visit(v, func() interface{} {
+			return s.sliceValue(v, depth)
+		})
+	case reflect.Array:
+		if s.tooDeep(depth) {
+			return maxDepthPlaceholder
+		}
+		return s.sliceValue(v, depth)
+	case reflect.Struct:
+		if s.tooDeep(depth) {
+			return maxDepthPlaceholder
+		}
+		return s.structValue(v, depth)
+	default:
+		return unserializable(t)
+	}
+}
+
+// userData sanitizes d with the limits of the payload, starting from depth 0.
+func (s *sanitizer) userData(d userData) interface{} {
+	active := s.active
+	s.active = s.limits
+	defer func() { s.active = active }()
+
+	return s.value(reflect.ValueOf(d.value), 0)
+}
+
+func (s *sanitizer) tooDeep(depth int) bool {
+	if s.active.maxDepth > 0 && depth >= s.active.maxDepth {
+		s.truncated = true
+		return true
+	}
+	return false
+}
+
+func (s *sanitizer) truncateString(str string) string {
+	str, truncated := s.active.truncateString(str)
+	s.truncated = s.truncated || truncated
+	return str
+}
+
+func (s *sanitizer) truncateBytes(b []byte) []byte {
+	if s.active.maxStringLength > 0 && len(b) > s.active.maxStringLength {
+		s.truncated = true
+		return b[:s.active.maxStringLength]
+	}
+	return b
+}
+
+// visit calls fn unless v is already being sanitized, which means it
+// contains itself.
+func (s *sanitizer) visit(v reflect.Value, fn func() interface{}) interface{} {
+	key := visit{v.UnsafePointer(), v.Type()}
+	if s.visiting[key] {
+		return cyclePlaceholder
+	}
+	s.visiting[key] = true
+	defer delete(s.visiting, key)
+	return fn()
+}
+
+// mapValue returns the entries of v. Maps with more than maxEntries entries
+// keep the entries with the lowest keys, and the number of entries dropped
+// under truncatedMarker.
+func (s *sanitizer) mapValue(v reflect.Value, depth int) interface{} {
+	values := make(map[string]reflect.Value, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		if key, ok := s.mapKey(iter.Key()); ok {
+			values[key] = iter.Value()
+		}
+	}
+
+	keys := slices.Sorted(maps.Keys(values))
+	dropped := 0
+	if s.active.maxEntries > 0 && len(keys) > s.active.maxEntries {
+		dropped = len(keys) - s.active.maxEntries
+		keys = keys[:s.active.maxEntries]
+		s.truncated = true
+	}
+
+	out := make(map[string]interface{}, len(keys)+1)
+	for _, key := range keys {
+		out[key] = s.value(values[key], depth+1)
+	}
+	if dropped > 0 {
+		out[truncatedMarker] = dropped
+	}
+	return out
+}
+
+// mapKey returns the JSON object key of k, following encoding/json.
+func (s *sanitizer) mapKey(k reflect.Value) (string, bool) {
+	if k.Kind() == reflect.String {
+		return k.String(), true
+	}
+	if k.Type().Implements(textMarshalerType) {
+		key, ok := s.marshalText(k).(string)
+		return key, ok
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		out, _ := json.Marshal(s.value(k, 0))
+		return string(out), true
+	}
+	return "", false
+}
+
+// sliceValue returns the elements of v. Slices with more than maxEntries
+// elements keep the first elements, followed by truncatedMarker.
+func (s *sanitizer) sliceValue(v reflect.Value, depth int) interface{} {
+	n := v.Len()
+	if s.active.maxEntries > 0 && n > s.active.maxEntries {
+		n = s.active.maxEntries
+		s.truncated = true
+	}
+
+	out := make([]interface{}, n, n+1)
+	for i := range out {
+		out[i] = s.value(v.Index(i), depth+1)
+	}
+	if n < v.Len() {
+		out = append(out, truncatedMarker)
+	}
+	return out
+}
+
+// structValue returns the exported fields of v, following the json struct
+// tags understood by encoding/json.
+func (s *sanitizer) structValue(v reflect.Value, depth int) interface{} {
+	out := make(map[string]interface{}, v.NumField())
+	jsonFields(v, func(name string, field reflect.Value) {
+		out[name] = s.value(field, depth+1)
+	})
+	return out
+}
+
+// jsonFields calls fn with the JSON name and value of each field of the
+// struct v serialized by encoding/json.
+func jsonFields(v reflect.Value, fn func(name string, field reflect.Value)) {
+	seen := make(map[string]bool, v.NumField())
+	for _, f := range reflect.VisibleFields(v.Type()) {
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// The fields of embedded structs are promoted, and listed
+		// separately by VisibleFields.
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		field, err := v.FieldByIndexErr(f.Index)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && isEmptyValue(field) {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if !seen[name] {
+			seen[name] = true
+			fn(name, field)
+		}
+	}
+}
+
+// isEmptyValue reports whether v is empty for the omitempty option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+// marshalJSON returns the JSON of a json.Marshaler. JSON longer than
+// maxStringLength is replaced by its truncated text.
+func (s *sanitizer) marshalJSON(v reflect.Value) (result interface{}) {
+	defer func() {
+		if recover() != nil {
+			result = unserializable(v.Type())
+		}
+	}()
+
+	out, err := v.Interface().(json.Marshaler).MarshalJSON()
+	if err != nil || !json.Valid(out) {
+		return unserializable(v.Type())
+	}
+	if s.active.maxStringLength > 0 && len(out) > s.active.maxStringLength {
+		return s.truncateString(string(out))
+	}
+	return json.RawMessage(out)
+}
+
+func (s *sanitizer) marshalText(v reflect.Value) (result interface{}) {
+	defer func() {
+		if recover() != nil {
+			result = unserializable(v.Type())
+		}
+	}()
+
+	out, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return unserializable(v.Type())
+	}
+	return s.truncateString(string(out))
+}
+
+// errorMessage returns the message of an error value, which encoding/json
+// would otherwise serialize as its fields.
+func (s *sanitizer) errorMessage(v reflect.Value) (result interface{}) {
+	defer func() {
+		if recover() != nil {
+			result = unserializable(v.Type())
+		}
+	}()
+
+	return s.truncateString(v.Interface().(error).Error())
+}
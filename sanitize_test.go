@@ -0,0 +1,159 @@
+package honeybadger
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+type panickingMarshaler struct{}
+
+func (panickingMarshaler) MarshalJSON() ([]byte, error) {
+	panic("badgers")
+}
+
+type taggedStruct struct {
+	Name     string `json:"name"`
+	Skipped  string `json:"-"`
+	Empty    string `json:"empty,omitempty"`
+	Untagged int
+	private  int
+	embedded
+}
+
+type embedded struct {
+	Promoted bool `json:"promoted"`
+}
+
+func sanitizeJSON(t *testing.T, value interface{}) interface{} {
+	var out interface{}
+	if err := json.Unmarshal(marshalPayload(value, defaultPayloadLimits), &out); err != nil {
+		t.Fatalf("Expected valid JSON. err=%v", err)
+	}
+	return out
+}
+
+func TestSanitizeUnserializable(t *testing.T) {
+	out := sanitizeJSON(t, hash{
+		"chan":    make(chan int),
+		"func":    func() {},
+		"nan":     math.NaN(),
+		"complex": complex(1, 2),
+		"panic":   panickingMarshaler{},
+		"error":   errors.New("cobras"),
+		"time":    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}).(map[string]interface{})
+
+	expected := map[string]interface{}{
+		"chan":    "[unserializable chan int]",
+		"func":    "[unserializable func()]",
+		"nan":     "[unserializable float64]",
+		"complex": "[unserializable complex128]",
+		"panic":   "[unserializable honeybadger.panickingMarshaler]",
+		"error":   "cobras",
+		"time":    "2026-01-02T03:04:05Z",
+	}
+	for k, v := range expected {
+		if out[k] != v {
+			t.Errorf("Unexpected sanitized value. key=%#v expected=%#v actual=%#v", k, v, out[k])
+		}
+	}
+}
+
+func TestSanitizeCycles(t *testing.T) {
+	m := map[string]interface{}{"name": "m"}
+	m["self"] = m
+
+	s := []interface{}{"s", nil}
+	s[1] = s
+
+	type node struct {
+		Next *node `json:"next"`
+	}
+	n := &node{}
+	n.Next = n
+
+	shared := map[string]interface{}{"shared": true}
+
+	out := sanitizeJSON(t, hash{"map": m, "slice": s, "node": n, "a": shared, "b": shared}).(map[string]interface{})
+
+	if self := out["map"].(map[string]interface{})["self"]; self != cyclePlaceholder {
+		t.Errorf("Expected map cycle placeholder. actual=%#v", self)
+	}
+	if self := out["slice"].([]interface{})[1]; self != cyclePlaceholder {
+		t.Errorf("Expected slice cycle placeholder. actual=%#v", self)
+	}
+	if next := out["node"].(map[string]interface{})["next"]; next != cyclePlaceholder {
+		t.Errorf("Expected pointer cycle placeholder. actual=%#v", next)
+	}
+	if out["a"] == cyclePlaceholder || out["b"] == cyclePlaceholder {
+		t.Errorf("Expected shared values not to be cycles. actual=%#v", out)
+	}
+}
+
+func TestSanitizeDepth(t *testing.T) {
+	var nested interface{} = "leaf"
+	for i := 0; i < maxPayloadDepth+5; i++ {
+		nested = []interface{}{nested}
+	}
+
+	out := sanitizeJSON(t, nested)
+	for i := 0; i < maxPayloadDepth; i++ {
+		out = out.([]interface{})[0]
+	}
+	if out != maxDepthPlaceholder {
+		t.Errorf("Expected max depth placeholder. actual=%#v", out)
+	}
+}
+
+func TestSanitizeStruct(t *testing.T) {
+	out := sanitizeJSON(t, taggedStruct{Name: "badger", Skipped: "x", Untagged: 1, private: 2, embedded: embedded{true}})
+
+	expected := map[string]interface{}{"name": "badger", "Untagged": 1.0, "promoted": true}
+	actual := out.(map[string]interface{})
+	if len(actual) != len(expected) {
+		t.Errorf("Unexpected struct fields. expected=%#v actual=%#v", expected, actual)
+	}
+	for k, v := range expected {
+		if actual[k] != v {
+			t.Errorf("Unexpected struct field. key=%#v expected=%#v actual=%#v", k, v, actual[k])
+		}
+	}
+}
+
+func TestNotifyUnserializableContext(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true})
+
+	context := Context{"chan": make(chan int)}
+	context["self"] = context
+	client.Notify("Cobras!", context)
+
+	var payload hash
+	if err := json.Unmarshal(backend.GetNotices()[0].toJSON(), &payload); err != nil {
+		t.Fatalf("Expected valid notice JSON. err=%v", err)
+	}
+	// The notice context is a copy of context, which contains itself.
+	request := payload["request"].(map[string]interface{})
+	actual := request["context"].(map[string]interface{})
+	if actual["chan"] != "[unserializable chan int]" {
+		t.Errorf("Expected placeholder for chan. actual=%#v", actual["chan"])
+	}
+	if self := actual["self"].(map[string]interface{})["self"]; self != cyclePlaceholder {
+		t.Errorf("Expected cycle placeholder. actual=%#v", self)
+	}
+}
+
+func TestEventPayloadUnserializable(t *testing.T) {
+	event := newEventPayload("log", nil, map[string]any{"callback": func() {}})
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(event.toJSON(), &payload); err != nil {
+		t.Fatalf("Expected valid event JSON. err=%v", err)
+	}
+	if payload["callback"] != "[unserializable func()]" {
+		t.Errorf("Expected placeholder for func. actual=%#v", payload["callback"])
+	}
+}
@@ -13,11 +13,12 @@ type preformattedAttr struct {
 }
 
 type Handler struct {
-	c         *honeybadger.Client
-	eventType string
-	preformat []preformattedAttr
-	groups    []string
-	level     slog.Leveler
+	c           *honeybadger.Client
+	eventType   string
+	preformat   []preformattedAttr
+	groups      []string
+	level       slog.Leveler
+	breadcrumbs bool
 }
 
 func New(c *honeybadger.Client) *Handler {
@@ -32,7 +33,7 @@ func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
 	return level >= h.level.Level()
 }
 
-func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
 	data := map[string]any{
 		"level":   r.Level.String(),
 		"message": r.Message,
@@ -54,6 +55,10 @@ func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 		return true
 	})
 
+	if h.breadcrumbs {
+		h.c.AddBreadcrumbContext(ctx, r.Message, "log", data)
+	}
+
 	return h.c.Event(eventType, data)
 }
 
@@ -102,11 +107,12 @@ func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	}
 
 	return &Handler{
-		c:         h.c,
-		eventType: h.eventType,
-		preformat: newPreformat,
-		groups:    h.groups,
-		level:     h.level,
+		c:           h.c,
+		eventType:   h.eventType,
+		preformat:   newPreformat,
+		groups:      h.groups,
+		level:       h.level,
+		breadcrumbs: h.breadcrumbs,
 	}
 }
 
@@ -118,11 +124,12 @@ func (h *Handler) WithGroup(name string) slog.Handler {
 	newGroups = append(newGroups, h.groups...)
 	newGroups = append(newGroups, name)
 	return &Handler{
-		c:         h.c,
-		eventType: h.eventType,
-		preformat: h.preformat,
-		groups:    newGroups,
-		level:     h.level,
+		c:           h.c,
+		eventType:   h.eventType,
+		preformat:   h.preformat,
+		groups:      newGroups,
+		level:       h.level,
+		breadcrumbs: h.breadcrumbs,
 	}
 }
 
@@ -131,20 +138,36 @@ func (h *Handler) WithEventType(eventType string) *Handler {
 		eventType = "log"
 	}
 	return &Handler{
-		c:         h.c,
-		eventType: eventType,
-		preformat: h.preformat,
-		groups:    h.groups,
-		level:     h.level,
+		c:           h.c,
+		eventType:   eventType,
+		preformat:   h.preformat,
+		groups:      h.groups,
+		level:       h.level,
+		breadcrumbs: h.breadcrumbs,
 	}
 }
 
 func (h *Handler) WithLevel(level slog.Leveler) *Handler {
 	return &Handler{
-		c:         h.c,
-		eventType: h.eventType,
-		preformat: h.preformat,
-		groups:    h.groups,
-		level:     level,
+		c:           h.c,
+		eventType:   h.eventType,
+		preformat:   h.preformat,
+		groups:      h.groups,
+		level:       level,
+		breadcrumbs: h.breadcrumbs,
+	}
+}
+
+// WithBreadcrumbs returns a handler which also records each log record as a
+// Honeybadger breadcrumb, on the trail of the record's context when it has
+// one.
+func (h *Handler) WithBreadcrumbs(enabled bool) *Handler {
+	return &Handler{
+		c:           h.c,
+		eventType:   h.eventType,
+		preformat:   h.preformat,
+		groups:      h.groups,
+		level:       h.level,
+		breadcrumbs: enabled,
 	}
 }
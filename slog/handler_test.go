@@ -1,6 +1,7 @@
 package hbslog
 
 import (
+	"context"
 	"log/slog"
 	"testing"
 
@@ -462,3 +463,33 @@ func TestEventTypePerLogCall(t *testing.T) {
 		t.Errorf("expected data event_type 'payment', got %v", events[1].Data["event_type"])
 	}
 }
+
+func TestWithBreadcrumbs(t *testing.T) {
+	client, _ := newTestClient()
+	logger := slog.New(New(client).WithBreadcrumbs(true))
+
+	ctx := honeybadger.WithBreadcrumbs(context.Background())
+	logger.InfoContext(ctx, "loading cart", "cart_id", 42)
+	logger.Info("client message")
+
+	client.Notify("Cobras!", ctx)
+
+	notices := client.Config.Backend.(*honeybadger.TestBackend).GetNotices()
+	if len(notices) != 1 {
+		t.Fatalf("expected 1 notice, got %d", len(notices))
+	}
+
+	crumbs := notices[0].Breadcrumbs
+	if len(crumbs) != 2 {
+		t.Fatalf("expected 2 breadcrumbs, got %d", len(crumbs))
+	}
+	if crumbs[0].Message != "loading cart" || crumbs[0].Category != "log" {
+		t.Errorf("unexpected breadcrumb %#v", crumbs[0])
+	}
+	if crumbs[0].Metadata["cart_id"] != int64(42) {
+		t.Errorf("expected cart_id=42, got %v", crumbs[0].Metadata["cart_id"])
+	}
+	if crumbs[1].Message != "client message" {
+		t.Errorf("unexpected breadcrumb %#v", crumbs[1])
+	}
+}
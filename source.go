@@ -0,0 +1,143 @@
+package honeybadger
+
+import (
+	"bufio"
+	"container/list"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxSourceFileSize is the largest file which will be read for source
+	// context; larger files are skipped.
+	maxSourceFileSize = 1 << 20
+
+	// maxSourceLineLength is the number of bytes kept of each source line.
+	maxSourceLineLength = 512
+
+	// maxSourceCacheFiles bounds the number of files kept in memory.
+	maxSourceCacheFiles = 256
+)
+
+// sourceCache holds the lines of the files read for source context, evicting
+// the least recently used files. Files which could not be read are cached as
+// nil so that the lookup isn't repeated.
+type sourceCache struct {
+	sync.Mutex
+	files map[string]*list.Element
+	order *list.List
+	limit int
+}
+
+type sourceFile struct {
+	path  string
+	lines []string
+}
+
+var sources = newSourceCache(maxSourceCacheFiles)
+
+func newSourceCache(limit int) *sourceCache {
+	return &sourceCache{
+		files: map[string]*list.Element{},
+		order: list.New(),
+		limit: limit,
+	}
+}
+
+func (c *sourceCache) lines(path string) []string {
+	c.Lock()
+	if e, ok := c.files[path]; ok {
+		c.order.MoveToFront(e)
+		c.Unlock()
+		return e.Value.(*sourceFile).lines
+	}
+	c.Unlock()
+
+	// Files are read without holding the lock, so that notices don't wait
+	// on each other's reads. Concurrent misses may read a file twice.
+	lines := readSourceLines(path)
+
+	c.Lock()
+	defer c.Unlock()
+
+	if e, ok := c.files[path]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*sourceFile).lines
+	}
+	c.files[path] = c.order.PushFront(&sourceFile{path: path, lines: lines})
+	if c.order.Len() > c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.files, oldest.Value.(*sourceFile).path)
+	}
+	return lines
+}
+
+func readSourceLines(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err != nil || !info.Mode().IsRegular() || info.Size() > maxSourceFileSize {
+		return nil
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSourceFileSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) > maxSourceLineLength {
+			line = line[:maxSourceLineLength]
+		}
+		lines = append(lines, line)
+	}
+	if scanner.Err() != nil {
+		return nil
+	}
+
+	return lines
+}
+
+// sourcePath resolves the normalized file of an application frame to a path
+// on disk relative to root.
+func sourcePath(file, root string) string {
+	if root == "" || !strings.HasPrefix(file, "[PROJECT_ROOT]") {
+		return ""
+	}
+	return filepath.Join(root, strings.TrimPrefix(file, "[PROJECT_ROOT]"))
+}
+
+// sourceContext returns radius lines of source before and after line in file,
+// keyed by line number. file is expected to be normalized by
+// normalizeFramePath. It returns nil when the source is unavailable.
+func sourceContext(file string, number string, root string, radius int) map[string]string {
+	line, err := strconv.Atoi(number)
+	if err != nil || radius <= 0 {
+		return nil
+	}
+
+	path := sourcePath(file, root)
+	if path == "" {
+		return nil
+	}
+
+	lines := sources.lines(path)
+	if line < 1 || line > len(lines) {
+		return nil
+	}
+
+	start := max(1, line-radius)
+	end := min(len(lines), line+radius)
+
+	source := make(map[string]string, end-start+1)
+	for i := start; i <= end; i++ {
+		source[strconv.Itoa(i)] = lines[i-1]
+	}
+	return source
+}
@@ -0,0 +1,120 @@
+package honeybadger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSourceFile(t *testing.T, root string, lines int) string {
+	var b strings.Builder
+	for i := 1; i <= lines; i++ {
+		b.WriteString("line ")
+		b.WriteString(strings.Repeat("x", i))
+		b.WriteString("\n")
+	}
+
+	path := filepath.Join(root, "badgers.go")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSourceContext(t *testing.T) {
+	root := t.TempDir()
+	writeSourceFile(t, root, 10)
+
+	source := sourceContext("[PROJECT_ROOT]/badgers.go", "5", root, 2)
+	if len(source) != 5 {
+		t.Fatalf("Expected 5 lines of source. actual=%#v", source)
+	}
+	if source["3"] != "line xxx" || source["7"] != "line xxxxxxx" {
+		t.Errorf("Unexpected source lines. actual=%#v", source)
+	}
+
+	source = sourceContext("[PROJECT_ROOT]/badgers.go", "1", root, 2)
+	if len(source) != 3 || source["1"] != "line x" {
+		t.Errorf("Expected source to be clamped to start of file. actual=%#v", source)
+	}
+
+	source = sourceContext("[PROJECT_ROOT]/badgers.go", "10", root, 1)
+	if len(source) != 2 || source["10"] != "line xxxxxxxxxx" {
+		t.Errorf("Expected source to be clamped to end of file. actual=%#v", source)
+	}
+}
+
+func TestSourceContextUnavailable(t *testing.T) {
+	root := t.TempDir()
+	path := writeSourceFile(t, root, 3)
+
+	if source := sourceContext("[PROJECT_ROOT]/badgers.go", "10", root, 2); source != nil {
+		t.Errorf("Expected no source for line outside file. actual=%#v", source)
+	}
+
+	if source := sourceContext("[PROJECT_ROOT]/missing.go", "1", root, 2); source != nil {
+		t.Errorf("Expected no source for missing file. actual=%#v", source)
+	}
+
+	if source := sourceContext(path, "1", root, 2); source != nil {
+		t.Errorf("Expected no source for file outside project root. actual=%#v", source)
+	}
+
+	if source := sourceContext("[PROJECT_ROOT]/badgers.go", "1", "", 2); source != nil {
+		t.Errorf("Expected no source without root. actual=%#v", source)
+	}
+}
+
+func TestNoticeSourceContext(t *testing.T) {
+	root := t.TempDir()
+	path := writeSourceFile(t, root, 10)
+
+	err := newTestError()
+	err.Stack = []*Frame{{File: path, Number: "4", Method: "badgers"}}
+
+	notice := newNotice(&Configuration{Root: root, SourceContextLines: 1}, err)
+	frame := notice.Backtrace[0]
+	if frame.File != "[PROJECT_ROOT]/badgers.go" {
+		t.Errorf("Expected notice to substitute project root. actual=%#v", frame.File)
+	}
+	if len(frame.Source) != 3 || frame.Source["4"] != "line xxxx" {
+		t.Errorf("Expected frame to include source. actual=%#v", frame.Source)
+	}
+
+	notice = newNotice(&Configuration{Root: root}, err)
+	if notice.Backtrace[0].Source != nil {
+		t.Errorf("Expected no source when disabled. actual=%#v", notice.Backtrace[0].Source)
+	}
+}
+
+func TestSourceCacheEviction(t *testing.T) {
+	root := t.TempDir()
+	paths := make([]string, 3)
+	for i := range paths {
+		paths[i] = filepath.Join(root, strings.Repeat("x", i+1)+".go")
+		if err := os.WriteFile(paths[i], []byte("before\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cache := newSourceCache(2)
+	cache.lines(paths[0])
+	cache.lines(paths[1])
+	cache.lines(paths[0])
+	cache.lines(paths[2])
+
+	for _, path := range paths {
+		os.WriteFile(path, []byte("after\n"), 0o644)
+	}
+
+	if lines := cache.lines(paths[0]); lines[0] != "before" {
+		t.Errorf("Expected recently used file to stay cached. actual=%#v", lines)
+	}
+	if lines := cache.lines(paths[1]); lines[0] != "after" {
+		t.Errorf("Expected least recently used file to be evicted. actual=%#v", lines)
+	}
+	if len(cache.files) != 2 || cache.order.Len() != 2 {
+		t.Errorf("Expected cache to be bounded. files=%d order=%d", len(cache.files), cache.order.Len())
+	}
+}
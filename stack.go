@@ -0,0 +1,166 @@
+package honeybadger
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Frame contexts understood by Honeybadger. Application frames are those
+// belonging to the project; all other frames belong to dependencies or the Go
+// runtime.
+const (
+	FrameContextApp = "app"
+	FrameContextAll = "all"
+)
+
+// packagePath is the import path of this package, used to recognize its own
+// frames in backtraces.
+const packagePath = "github.com/honeybadger-io/honeybadger-go"
+
+// DefaultFrameFilter is the default Configuration.FrameFilter. It removes the
+// frames of honeybadger-go itself, such as Monitor and the Handler closure,
+// from backtraces.
+func DefaultFrameFilter(frame *Frame) *Frame {
+	if isInternalFrame(frame) {
+		return nil
+	}
+	return frame
+}
+
+func isInternalFrame(frame *Frame) bool {
+	pkg := framePackage(frame.Method)
+	return pkg == packagePath || strings.HasPrefix(pkg, packagePath+"/")
+}
+
+// framePackage returns the import path of the package a function belongs to,
+// given its fully qualified name such as "net/http.(*Server).Serve".
+func framePackage(method string) string {
+	slash := strings.LastIndex(method, "/")
+	if dot := strings.Index(method[slash+1:], "."); dot >= 0 {
+		return method[:slash+1+dot]
+	}
+	return method
+}
+
+// buildPaths holds the path prefixes used to classify backtrace frames.
+type buildPaths struct {
+	// goroot is the GOROOT the binary was built with, or empty when built
+	// with -trimpath.
+	goroot string
+
+	// mainModule is the module path of the main package.
+	mainModule string
+
+	// modules are the escaped path@version prefixes of the dependencies, as
+	// they appear in the module cache and in -trimpath builds.
+	modules []string
+}
+
+var loadBuildPaths = sync.OnceValue(func() *buildPaths {
+	paths := &buildPaths{goroot: detectGoroot()}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return paths
+	}
+
+	paths.mainModule = info.Main.Path
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if dep.Version == "" {
+			continue
+		}
+		paths.modules = append(paths.modules, escapeModulePath(dep.Path)+"@"+dep.Version+"/")
+	}
+
+	return paths
+})
+
+// detectGoroot returns the GOROOT recorded in the runtime's own file names.
+// runtime.GOROOT is deprecated and reports the environment rather than the
+// build, so the path is derived from a known runtime function instead.
+func detectGoroot() string {
+	pc := reflect.ValueOf(runtime.Goexit).Pointer()
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+
+	file, _ := fn.FileLine(pc)
+	if i := strings.LastIndex(file, "/src/runtime/"); i > 0 {
+		return file[:i]
+	}
+	return ""
+}
+
+// escapeModulePath applies the module cache's case encoding, where each upper
+// case letter is replaced by an exclamation mark and its lower case form.
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// normalizeFramePath replaces the project root, GOROOT and module cache
+// prefixes of file with placeholders, and reports whether the frame belongs
+// to the application.
+func normalizeFramePath(file, root string) (string, bool) {
+	return loadBuildPaths().normalize(file, root)
+}
+
+func (paths *buildPaths) normalize(file, root string) (string, bool) {
+	// The GOROOT and module cache are checked first, since they are often
+	// inside the project root, such as when it defaults to $HOME.
+	if paths.goroot != "" && strings.HasPrefix(file, paths.goroot+"/") {
+		return "[GOROOT]" + strings.TrimPrefix(file, paths.goroot), false
+	}
+
+	for _, module := range paths.modules {
+		if strings.HasPrefix(file, module) {
+			return file, false
+		}
+		if i := strings.Index(file, "/"+module); i >= 0 {
+			return file[i+1:], false
+		}
+	}
+
+	if i := strings.Index(file, "/pkg/mod/"); i >= 0 && strings.Contains(file[i:], "@") {
+		return file[i+len("/pkg/mod/"):], false
+	}
+
+	if root = strings.TrimSuffix(root, "/"); root != "" && strings.HasPrefix(file, root+"/") {
+		rel := strings.TrimPrefix(file, root)
+		return "[PROJECT_ROOT]" + rel, !strings.HasPrefix(rel, "/vendor/")
+	}
+
+	if file == "" || filepath.IsAbs(file) {
+		return file, false
+	}
+
+	// The remaining relative paths come from -trimpath builds, which record
+	// main module files under the module path and standard library files
+	// relative to GOROOT/src.
+	if paths.mainModule != "" && strings.HasPrefix(file, paths.mainModule+"/") {
+		return "[PROJECT_ROOT]" + strings.TrimPrefix(file, paths.mainModule), true
+	}
+
+	first, _, _ := strings.Cut(file, "/")
+	if !strings.Contains(first, ".") && !strings.Contains(file, "@") {
+		return "[GOROOT]/src/" + file, false
+	}
+
+	return file, false
+}
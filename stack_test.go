@@ -0,0 +1,139 @@
+package honeybadger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeFramePath(t *testing.T) {
+	paths := &buildPaths{
+		goroot:     "/usr/local/go",
+		mainModule: "example.com/app",
+		modules:    []string{"github.com/!burnt!sushi/toml@v1.3.2/"},
+	}
+
+	tests := []struct {
+		file string
+		want string
+		app  bool
+	}{
+		{"/path/to/root/badgers.go", "[PROJECT_ROOT]/badgers.go", true},
+		{"/usr/local/go/src/runtime/panic.go", "[GOROOT]/src/runtime/panic.go", false},
+		{"/home/me/go/pkg/mod/github.com/!burnt!sushi/toml@v1.3.2/decode.go", "github.com/!burnt!sushi/toml@v1.3.2/decode.go", false},
+		{"/cache/mod/github.com/!burnt!sushi/toml@v1.3.2/decode.go", "github.com/!burnt!sushi/toml@v1.3.2/decode.go", false},
+		{"/home/me/go/pkg/mod/example.com/other@v0.1.0/other.go", "example.com/other@v0.1.0/other.go", false},
+		{"/foo/bar/baz.go", "/foo/bar/baz.go", false},
+
+		// -trimpath builds
+		{"example.com/app/handlers/users.go", "[PROJECT_ROOT]/handlers/users.go", true},
+		{"github.com/!burnt!sushi/toml@v1.3.2/decode.go", "github.com/!burnt!sushi/toml@v1.3.2/decode.go", false},
+		{"net/http/server.go", "[GOROOT]/src/net/http/server.go", false},
+	}
+
+	for _, tt := range tests {
+		file, app := paths.normalize(tt.file, "/path/to/root")
+		if file != tt.want || app != tt.app {
+			t.Errorf("normalize(%q) expected=(%q, %v) actual=(%q, %v)", tt.file, tt.want, tt.app, file, app)
+		}
+	}
+
+	// Dependencies and GOROOT inside the project root, such as when it is
+	// $HOME, are not application frames.
+	rootTests := []struct {
+		root string
+		file string
+		want string
+		app  bool
+	}{
+		{"/home/u", "/home/u/go/pkg/mod/github.com/pkg/errors@v0.9.1/errors.go", "github.com/pkg/errors@v0.9.1/errors.go", false},
+		{"/home/u", "/home/u/go/pkg/mod/github.com/!burnt!sushi/toml@v1.3.2/decode.go", "github.com/!burnt!sushi/toml@v1.3.2/decode.go", false},
+		{"/usr/local", "/usr/local/go/src/runtime/panic.go", "[GOROOT]/src/runtime/panic.go", false},
+		{"/path/to/root", "/path/to/root/vendor/github.com/pkg/errors/errors.go", "[PROJECT_ROOT]/vendor/github.com/pkg/errors/errors.go", false},
+		{"/path/to/root/", "/path/to/root/badgers.go", "[PROJECT_ROOT]/badgers.go", true},
+		{"/path/to/root", "/path/to/rootless/badgers.go", "/path/to/rootless/badgers.go", false},
+	}
+
+	for _, tt := range rootTests {
+		file, app := paths.normalize(tt.file, tt.root)
+		if file != tt.want || app != tt.app {
+			t.Errorf("normalize(%q, %q) expected=(%q, %v) actual=(%q, %v)", tt.file, tt.root, tt.want, tt.app, file, app)
+		}
+	}
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	if got := escapeModulePath("github.com/BurntSushi/toml"); got != "github.com/!burnt!sushi/toml" {
+		t.Errorf("Unexpected escaped path. expected=%q actual=%q", "github.com/!burnt!sushi/toml", got)
+	}
+}
+
+func TestComposeStackContext(t *testing.T) {
+	goroot := loadBuildPaths().goroot
+	if goroot == "" {
+		t.Skip("binary built without GOROOT paths")
+	}
+
+	stack := []*Frame{
+		{File: "/path/to/root/badgers.go", Number: "1", Method: "badgers"},
+		{File: goroot + "/src/runtime/panic.go", Number: "2", Method: "runtime.gopanic"},
+	}
+
+	frames := composeStack(stack, &Configuration{Root: "/path/to/root"})
+	if frames[0].Context != FrameContextApp {
+		t.Errorf("Expected application frame. actual=%#v", frames[0])
+	}
+	if frames[1].Context != FrameContextAll || !strings.HasPrefix(frames[1].File, "[GOROOT]/") {
+		t.Errorf("Expected runtime frame. actual=%#v", frames[1])
+	}
+}
+
+func TestDefaultFrameFilter(t *testing.T) {
+	tests := []struct {
+		frame *Frame
+		kept  bool
+	}{
+		{&Frame{File: "/src/honeybadger-go/client.go", Method: "github.com/honeybadger-io/honeybadger-go.(*Client).Handler.func1"}, false},
+		{&Frame{File: "/src/honeybadger-go/honeybadger.go", Method: "github.com/honeybadger-io/honeybadger-go.Monitor"}, false},
+		{&Frame{File: "/src/honeybadger-go/slog/handler.go", Method: "github.com/honeybadger-io/honeybadger-go/slog.(*Handler).Handle"}, false},
+		{&Frame{File: "/src/honeybadger-go/error_test.go", Method: "github.com/honeybadger-io/honeybadger-go.TestNewErrorTrace"}, false},
+		{&Frame{File: "/src/honeybadger-gopher/main.go", Method: "github.com/honeybadger-io/honeybadger-gopher.main"}, true},
+		{&Frame{File: "/usr/local/go/src/net/http/server.go", Method: "net/http.HandlerFunc.ServeHTTP"}, true},
+	}
+
+	for _, tt := range tests {
+		if kept := DefaultFrameFilter(tt.frame) != nil; kept != tt.kept {
+			t.Errorf("DefaultFrameFilter(%q) expected kept=%v actual=%v", tt.frame.Method, tt.kept, kept)
+		}
+	}
+}
+
+func TestComposeStackFilterAndLimit(t *testing.T) {
+	stack := []*Frame{
+		{File: "/path/to/root/a.go", Number: "1", Method: "main.a"},
+		{File: "/path/to/root/skip.go", Number: "2", Method: "main.skip"},
+		{File: "/path/to/root/b.go", Number: "3", Method: "main.b"},
+		{File: "/path/to/root/c.go", Number: "4", Method: "main.c"},
+	}
+
+	frames := composeStack(stack, &Configuration{
+		Root:      "/path/to/root",
+		MaxFrames: 2,
+		FrameFilter: func(frame *Frame) *Frame {
+			if frame.Method == "main.skip" {
+				return nil
+			}
+			frame.Method = strings.TrimPrefix(frame.Method, "main.")
+			return frame
+		},
+	})
+
+	if len(frames) != 2 {
+		t.Fatalf("Expected frames to be limited to MaxFrames. actual=%d", len(frames))
+	}
+	if frames[0].Method != "a" || frames[1].Method != "b" {
+		t.Errorf("Expected filtered and transformed frames. actual=%q, %q", frames[0].Method, frames[1].Method)
+	}
+	if stack[0].Method != "main.a" {
+		t.Errorf("Expected original stack to be unchanged. actual=%q", stack[0].Method)
+	}
+}
@@ -0,0 +1,214 @@
+package honeybadger
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultCgroupRoot is where the cgroup filesystem is mounted on Linux.
+const DefaultCgroupRoot = "/sys/fs/cgroup"
+
+// The StatsProvider interface is implemented by types which provide the
+// system stats reported in the server section of notices, such as memory and
+// load. The Go runtime statistics are always added.
+type StatsProvider interface {
+	Stats() map[string]interface{}
+}
+
+// nullStatsProvider implements the StatsProvider interface but reports no
+// stats.
+type nullStatsProvider struct{}
+
+// NewNullStatsProvider creates a StatsProvider which reports no system stats.
+func NewNullStatsProvider() StatsProvider {
+	return &nullStatsProvider{}
+}
+
+// Stats returns no stats.
+func (*nullStatsProvider) Stats() map[string]interface{} {
+	return nil
+}
+
+// cgroupStatsProvider reports the memory and CPU limits of the cgroup of the
+// process, which are the limits of its container.
+type cgroupStatsProvider struct {
+	root string
+
+	// self lists the cgroups of the process, as in /proc/self/cgroup.
+	self string
+}
+
+// NewCgroupStatsProvider creates a StatsProvider which reports the memory
+// usage and limit, and the CPU limit, of the cgroup of the process in the
+// cgroup v1 or v2 hierarchy mounted at root, usually DefaultCgroupRoot.
+// Limits are omitted when unlimited, and no stats are reported when root
+// isn't a cgroup hierarchy.
+func NewCgroupStatsProvider(root string) StatsProvider {
+	return &cgroupStatsProvider{root: root, self: "/proc/self/cgroup"}
+}
+
+// Stats returns the "mem" stats in KB, and the "cpu" limit in cores.
+func (p *cgroupStatsProvider) Stats() map[string]interface{} {
+	cgroups := readCgroups(p.self)
+	var usage, limit, cpus float64
+	var ok bool
+	if fileExists(filepath.Join(p.root, "cgroup.controllers")) {
+		usage, limit, cpus, ok = p.readV2(cgroups)
+	} else {
+		usage, limit, cpus, ok = p.readV1(cgroups)
+	}
+	if !ok {
+		return nil
+	}
+
+	mem := hash{"used": usage / 1024}
+	if limit > 0 {
+		free := math.Max(limit-usage, 0) / 1024
+		mem["total"] = limit / 1024
+		mem["free"] = free
+		mem["free_total"] = free
+	}
+	stats := map[string]interface{}{"mem": &mem}
+	if cpus > 0 {
+		stats["cpu"] = &hash{"limit": cpus}
+	}
+	return stats
+}
+
+func (p *cgroupStatsProvider) readV2(cgroups []cgroup) (usage, limit, cpus float64, ok bool) {
+	// The unified hierarchy has the ID 0 and no controllers.
+	dir := p.root
+	if i := slices.IndexFunc(cgroups, func(c cgroup) bool { return c.id == "0" && c.controllers == "" }); i >= 0 {
+		dir = cgroupDir(p.root, cgroups[i].path)
+	}
+
+	usage, ok = readCgroupNumber(filepath.Join(dir, "memory.current"))
+	if !ok {
+		return 0, 0, 0, false
+	}
+	limit, _ = readCgroupNumber(filepath.Join(dir, "memory.max"))
+
+	// cpu.max holds the quota and period, or "max" when unlimited.
+	if fields := strings.Fields(readCgroupFile(filepath.Join(dir, "cpu.max"))); len(fields) == 2 {
+		quota, err1 := strconv.ParseFloat(fields[0], 64)
+		period, err2 := strconv.ParseFloat(fields[1], 64)
+		if err1 == nil && err2 == nil && period > 0 {
+			cpus = quota / period
+		}
+	}
+	return usage, limit, cpus, true
+}
+
+func (p *cgroupStatsProvider) readV1(cgroups []cgroup) (usage, limit, cpus float64, ok bool) {
+	memory := p.controllerDir(cgroups, "memory", "memory")
+	usage, ok = readCgroupNumber(filepath.Join(memory, "memory.usage_in_bytes"))
+	if !ok {
+		return 0, 0, 0, false
+	}
+
+	// Unlimited cgroups report a limit close to the maximum int64.
+	limit, _ = readCgroupNumber(filepath.Join(memory, "memory.limit_in_bytes"))
+	if limit >= 1<<62 {
+		limit = 0
+	}
+
+	cpu := p.controllerDir(cgroups, "cpu", "cpu")
+	if !fileExists(cpu) {
+		cpu = p.controllerDir(cgroups, "cpu", "cpu,cpuacct")
+	}
+	quota, _ := readCgroupNumber(filepath.Join(cpu, "cpu.cfs_quota_us"))
+	period, _ := readCgroupNumber(filepath.Join(cpu, "cpu.cfs_period_us"))
+	if quota > 0 && period > 0 {
+		cpus = quota / period
+	}
+	return usage, limit, cpus, true
+}
+
+// controllerDir returns the directory of the v1 cgroup of the process for
+// controller. Hierarchies are mounted under root by the names of their
+// controllers, such as "cpu,cpuacct"; the hierarchy at mount is used when
+// the process doesn't list the controller.
+func (p *cgroupStatsProvider) controllerDir(cgroups []cgroup, controller, mount string) string {
+	for _, c := range cgroups {
+		if slices.Contains(strings.Split(c.controllers, ","), controller) {
+			return cgroupDir(filepath.Join(p.root, c.controllers), c.path)
+		}
+	}
+	return filepath.Join(p.root, mount)
+}
+
+// cgroupDir returns the directory of the cgroup at path in the hierarchy
+// mounted at mount. In a container with a private cgroup namespace, or with
+// its own cgroup mounted as the root of the hierarchy, the cgroup path
+// doesn't exist under mount, and mount itself is the cgroup of the process.
+func cgroupDir(mount, path string) string {
+	if path == "/" {
+		return mount
+	}
+	if dir := filepath.Join(mount, path); fileExists(dir) {
+		return dir
+	}
+	return mount
+}
+
+// cgroup is a line of /proc/self/cgroup, in the form
+// "hierarchy-ID:controller-list:cgroup-path".
+type cgroup struct {
+	id          string
+	controllers string
+	path        string
+}
+
+func readCgroups(path string) []cgroup {
+	var cgroups []cgroup
+	for _, line := range strings.Split(readCgroupFile(path), "\n") {
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		cgroups = append(cgroups, cgroup{id: fields[0], controllers: fields[1], path: fields[2]})
+	}
+	return cgroups
+}
+
+func readCgroupFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readCgroupNumber reads a number from a cgroup file. A value of "max" is
+// reported as 0, meaning unlimited.
+func readCgroupNumber(path string) (float64, bool) {
+	value := readCgroupFile(path)
+	if value == "max" {
+		return 0, true
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// getStats returns the system stats of a StatsProvider along with the Go
+// runtime statistics.
+func getStats(system map[string]interface{}) *hash {
+	stats := hash{}
+	for k, v := range system {
+		stats[k] = v
+	}
+	stats["runtime"] = runtimeStats()
+	return &stats
+}
@@ -0,0 +1,68 @@
+package honeybadger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// statsSampler reads the stats of a StatsProvider in the background, so that
+// notices don't read them. The stats are read when the sampler is created and
+// then every interval, while notices use the latest snapshot.
+type statsSampler struct {
+	snapshot atomic.Pointer[map[string]interface{}]
+
+	done chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+func newStatsSampler(provider StatsProvider, interval time.Duration) *statsSampler {
+	s := &statsSampler{done: make(chan struct{})}
+	s.refresh(provider)
+	if provider != nil && interval > 0 {
+		s.wg.Add(1)
+		go s.run(provider, interval)
+	}
+	return s
+}
+
+func (s *statsSampler) run(provider StatsProvider, interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.refresh(provider)
+		}
+	}
+}
+
+func (s *statsSampler) refresh(provider StatsProvider) {
+	var stats map[string]interface{}
+	if provider != nil {
+		stats = provider.Stats()
+	}
+	s.snapshot.Store(&stats)
+}
+
+// stats returns the latest snapshot of the stats.
+func (s *statsSampler) stats() map[string]interface{} {
+	if stats := s.snapshot.Load(); stats != nil {
+		return *stats
+	}
+	return nil
+}
+
+// Stop stops refreshing the stats.
+func (s *statsSampler) Stop() {
+	s.once.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+	})
+}
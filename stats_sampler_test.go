@@ -0,0 +1,95 @@
+package honeybadger
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingStatsProvider struct {
+	calls atomic.Int64
+}
+
+func (p *countingStatsProvider) Stats() map[string]interface{} {
+	return map[string]interface{}{"calls": p.calls.Add(1)}
+}
+
+func TestStatsSampler(t *testing.T) {
+	provider := &countingStatsProvider{}
+	sampler := newStatsSampler(provider, 10*time.Millisecond)
+	defer sampler.Stop()
+
+	if stats := sampler.stats(); stats["calls"] != int64(1) {
+		t.Errorf("Expected stats to be read at startup. actual=%#v", stats)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for provider.calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if calls := provider.calls.Load(); calls < 3 {
+		t.Errorf("Expected stats to be refreshed in the background. calls=%d", calls)
+	}
+
+	sampler.Stop()
+	calls := provider.calls.Load()
+	time.Sleep(50 * time.Millisecond)
+	if actual := provider.calls.Load(); actual != calls {
+		t.Errorf("Expected no refresh after stop. expected=%d actual=%d", calls, actual)
+	}
+}
+
+func TestNotifyStatsSnapshot(t *testing.T) {
+	backend := &TestBackend{}
+	provider := &countingStatsProvider{}
+	client := New(Configuration{Backend: backend, Sync: true, StatsProvider: provider, StatsInterval: time.Hour})
+	defer client.Stop()
+
+	if calls := provider.calls.Load(); calls != 1 {
+		t.Errorf("Expected stats to be read when the client is created. actual=%d", calls)
+	}
+
+	client.Notify("Cobras!")
+	client.Notify("Cobras!")
+
+	if calls := provider.calls.Load(); calls != 1 {
+		t.Errorf("Expected notices not to read stats. actual=%d", calls)
+	}
+
+	notice := backend.GetNotices()[1]
+	notice.toJSON()
+	if calls := provider.calls.Load(); calls != 1 {
+		t.Errorf("Expected serialization not to read stats. actual=%d", calls)
+	}
+	if stats := *notice.stats; stats["calls"] != int64(1) || stats["runtime"] == nil {
+		t.Errorf("Expected snapshot and runtime stats. actual=%#v", stats)
+	}
+}
+
+func TestNotifyStatsSkippedNotices(t *testing.T) {
+	client := New(Configuration{Backend: &TestBackend{}, Sync: true, StatsInterval: time.Hour})
+	defer client.Stop()
+
+	var notice *Notice
+	client.BeforeNotify(func(n *Notice) error {
+		notice = n
+		return ErrNoticeIgnored
+	})
+	client.Notify("Cobras!")
+
+	if notice == nil || notice.stats != nil {
+		t.Errorf("Expected no stats for a notice skipped by BeforeNotify. actual=%#v", notice)
+	}
+}
+
+func TestConfigureStatsSampler(t *testing.T) {
+	client := New(Configuration{Backend: &TestBackend{}, StatsInterval: time.Hour})
+	defer client.Stop()
+
+	provider := &countingStatsProvider{}
+	client.Configure(Configuration{StatsProvider: provider})
+
+	if stats := client.statsSampler.stats(); stats["calls"] != int64(1) {
+		t.Errorf("Expected stats of the configured provider. actual=%#v", stats)
+	}
+}
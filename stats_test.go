@@ -0,0 +1,151 @@
+package honeybadger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCgroupFiles(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// cgroupStatsProviderFor creates a cgroupStatsProvider for the hierarchy at
+// root, reading the cgroups of the process from self.
+func cgroupStatsProviderFor(t *testing.T, root, self string) StatsProvider {
+	path := filepath.Join(t.TempDir(), "cgroup")
+	if err := os.WriteFile(path, []byte(self), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return &cgroupStatsProvider{root: root, self: path}
+}
+
+func TestCgroupStatsProviderV2(t *testing.T) {
+	root := writeCgroupFiles(t, map[string]string{
+		"cgroup.controllers":           "cpu memory",
+		"memory.current":               "8388608",
+		"app.slice/cgroup.controllers": "cpu memory",
+		"app.slice/memory.current":     "1048576",
+		"app.slice/memory.max":         "4194304",
+		"app.slice/cpu.max":            "150000 100000",
+	})
+
+	stats := cgroupStatsProviderFor(t, root, "0::/app.slice\n").Stats()
+
+	mem := *stats["mem"].(*hash)
+	if mem["used"] != 1024.0 || mem["total"] != 4096.0 || mem["free"] != 3072.0 {
+		t.Errorf("Unexpected mem stats. actual=%#v", mem)
+	}
+	if cpu := *stats["cpu"].(*hash); cpu["limit"] != 1.5 {
+		t.Errorf("Unexpected cpu stats. actual=%#v", cpu)
+	}
+}
+
+func TestCgroupStatsProviderV2Unlimited(t *testing.T) {
+	root := writeCgroupFiles(t, map[string]string{
+		"cgroup.controllers":       "cpu memory",
+		"app.slice/memory.current": "1048576",
+		"app.slice/memory.max":     "max",
+		"app.slice/cpu.max":        "max 100000",
+	})
+
+	stats := cgroupStatsProviderFor(t, root, "0::/app.slice\n").Stats()
+
+	mem := *stats["mem"].(*hash)
+	if _, ok := mem["total"]; ok || mem["used"] != 1024.0 {
+		t.Errorf("Expected only memory usage without limit. actual=%#v", mem)
+	}
+	if _, ok := stats["cpu"]; ok {
+		t.Errorf("Expected no cpu limit. actual=%#v", stats["cpu"])
+	}
+}
+
+func TestCgroupStatsProviderV1(t *testing.T) {
+	root := writeCgroupFiles(t, map[string]string{
+		"memory/docker/abc/memory.usage_in_bytes":  "2097152",
+		"memory/docker/abc/memory.limit_in_bytes":  "9223372036854771712",
+		"cpu,cpuacct/docker/abc/cpu.cfs_quota_us":  "200000",
+		"cpu,cpuacct/docker/abc/cpu.cfs_period_us": "100000",
+	})
+
+	stats := cgroupStatsProviderFor(t, root, "12:memory:/docker/abc\n4:cpu,cpuacct:/docker/abc\n1:name=systemd:/docker/abc\n").Stats()
+
+	mem := *stats["mem"].(*hash)
+	if _, ok := mem["total"]; ok || mem["used"] != 2048.0 {
+		t.Errorf("Expected unlimited memory to be omitted. actual=%#v", mem)
+	}
+	if cpu := *stats["cpu"].(*hash); cpu["limit"] != 2.0 {
+		t.Errorf("Unexpected cpu stats. actual=%#v", cpu)
+	}
+}
+
+func TestCgroupStatsProviderNamespace(t *testing.T) {
+	// With a private cgroup namespace, the process is in the root of the
+	// hierarchy, which holds the limits of its container.
+	root := writeCgroupFiles(t, map[string]string{
+		"cgroup.controllers": "cpu memory",
+		"memory.current":     "1048576",
+		"memory.max":         "4194304",
+		"cpu.max":            "50000 100000",
+	})
+	stats := cgroupStatsProviderFor(t, root, "0::/\n").Stats()
+	if mem := *stats["mem"].(*hash); mem["used"] != 1024.0 || mem["total"] != 4096.0 {
+		t.Errorf("Expected v2 container mem stats. actual=%#v", mem)
+	}
+	if cpu := *stats["cpu"].(*hash); cpu["limit"] != 0.5 {
+		t.Errorf("Expected v2 container cpu stats. actual=%#v", cpu)
+	}
+
+	// cgroup v1 containers list the cgroup of the host, while their own
+	// cgroup is mounted at the root of each hierarchy.
+	root = writeCgroupFiles(t, map[string]string{
+		"memory/memory.usage_in_bytes":  "2097152",
+		"memory/memory.limit_in_bytes":  "8388608",
+		"cpu,cpuacct/cpu.cfs_quota_us":  "100000",
+		"cpu,cpuacct/cpu.cfs_period_us": "100000",
+	})
+	stats = cgroupStatsProviderFor(t, root, "12:memory:/docker/abc\n4:cpu,cpuacct:/docker/abc\n").Stats()
+	if mem := *stats["mem"].(*hash); mem["used"] != 2048.0 || mem["total"] != 8192.0 {
+		t.Errorf("Expected v1 container mem stats. actual=%#v", mem)
+	}
+	if cpu := *stats["cpu"].(*hash); cpu["limit"] != 1.0 {
+		t.Errorf("Expected v1 container cpu stats. actual=%#v", cpu)
+	}
+}
+
+func TestCgroupStatsProviderMissing(t *testing.T) {
+	if stats := cgroupStatsProviderFor(t, t.TempDir(), "0::/app.slice\n").Stats(); stats != nil {
+		t.Errorf("Expected no stats without cgroup. actual=%#v", stats)
+	}
+	if stats := NewCgroupStatsProvider(t.TempDir()).Stats(); stats != nil {
+		t.Errorf("Expected no stats without cgroup. actual=%#v", stats)
+	}
+}
+
+type staticStatsProvider map[string]interface{}
+
+func (p staticStatsProvider) Stats() map[string]interface{} {
+	return p
+}
+
+func TestGetStats(t *testing.T) {
+	stats := *getStats(staticStatsProvider{"load": 1.0}.Stats())
+	if stats["load"] != 1.0 || stats["runtime"] == nil {
+		t.Errorf("Expected provider and runtime stats. actual=%#v", stats)
+	}
+
+	stats = *getStats(NewNullStatsProvider().Stats())
+	if len(stats) != 1 || stats["runtime"] == nil {
+		t.Errorf("Expected only runtime stats. actual=%#v", stats)
+	}
+}
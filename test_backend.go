@@ -3,8 +3,16 @@ package honeybadger
 import "sync"
 
 type TestBackend struct {
-	Events []EventData
-	mu     sync.Mutex
+	Events   []EventData
+	Notices  []*Notice
+	CheckIns []CheckInData
+	Deploys  []Deploy
+	mu       sync.Mutex
+}
+
+type CheckInData struct {
+	ID   string
+	Slug string
 }
 
 type EventData struct {
@@ -12,7 +20,22 @@ type EventData struct {
 	Data      map[string]any
 }
 
-func (b *TestBackend) Notify(_ Feature, _ Payload) error {
+func (b *TestBackend) Notify(_ Feature, payload Payload) error {
+	if notice, ok := payload.(*Notice); ok {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.Notices = append(b.Notices, notice)
+	}
+	if checkIn, ok := payload.(*checkIn); ok {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.CheckIns = append(b.CheckIns, CheckInData{ID: checkIn.id, Slug: checkIn.slug})
+	}
+	if deploy, ok := payload.(*Deploy); ok {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.Deploys = append(b.Deploys, *deploy)
+	}
 	return nil
 }
 
@@ -34,3 +57,21 @@ func (b *TestBackend) GetEvents() []EventData {
 	defer b.mu.Unlock()
 	return b.Events
 }
+
+func (b *TestBackend) GetNotices() []*Notice {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Notices
+}
+
+func (b *TestBackend) GetCheckIns() []CheckInData {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.CheckIns
+}
+
+func (b *TestBackend) GetDeploys() []Deploy {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Deploys
+}
@@ -0,0 +1,39 @@
+package honeybadger
+
+import "context"
+
+// User identifies the user affected by an error. Honeybadger counts the
+// affected users of an error by their ID and email.
+type User struct {
+	ID         string
+	Email      string
+	Name       string
+	Attributes map[string]interface{}
+}
+
+// Context returns the user as the context keys understood by Honeybadger:
+// user_id, user_email and user_name. Each attribute is prefixed with "user_".
+func (u User) Context() Context {
+	context := make(Context, len(u.Attributes)+3)
+	for k, v := range u.Attributes {
+		context["user_"+k] = v
+	}
+	if u.ID != "" {
+		context["user_id"] = u.ID
+	}
+	if u.Email != "" {
+		context["user_email"] = u.Email
+	}
+	if u.Name != "" {
+		context["user_name"] = u.Name
+	}
+	return context
+}
+
+// WithUser returns a copy of ctx carrying user, which is sent with notices
+// reported with NotifyContext for the returned context.
+func WithUser(ctx context.Context, user User) context.Context {
+	scope := noticeScopeFromContext(ctx)
+	scope.user = &user
+	return context.WithValue(ctx, noticeScopeKey{}, scope)
+}
@@ -0,0 +1,74 @@
+package honeybadger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUserContext(t *testing.T) {
+	user := User{ID: "42", Email: "badger@example.com", Attributes: map[string]interface{}{"plan": "pro"}}
+	context := user.Context()
+
+	if context["user_id"] != "42" || context["user_email"] != "badger@example.com" || context["user_plan"] != "pro" {
+		t.Errorf("Unexpected user context. actual=%#v", context)
+	}
+	if _, ok := context["user_name"]; ok {
+		t.Errorf("Expected empty fields to be omitted. actual=%#v", context)
+	}
+}
+
+func TestNotifyWithUser(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true})
+
+	client.SetUser(User{ID: "1", Email: "client@example.com"})
+	client.Notify("Cobras!")
+
+	ctx := WithUser(context.Background(), User{ID: "2"})
+	client.NotifyContext(ctx, "Cobras!")
+
+	client.Notify("Cobras!", User{ID: "3", Name: "Badger"})
+
+	client.ClearUser()
+	client.Notify("Cobras!")
+
+	notices := backend.GetNotices()
+	if len(notices) != 4 {
+		t.Fatalf("Expected 4 notices. actual=%d", len(notices))
+	}
+
+	for i, id := range []string{"1", "2", "3"} {
+		if context := notices[i].context(); context["user_id"] != id {
+			t.Errorf("notices[%d] expected user_id=%q actual=%#v", i, id, context)
+		}
+	}
+
+	if context := notices[1].context(); context["user_email"] != nil {
+		t.Errorf("Expected context user to replace client user. actual=%#v", context)
+	}
+
+	if notices[3].User != nil {
+		t.Errorf("Expected no user after ClearUser. actual=%#v", notices[3].User)
+	}
+}
+
+func TestEventWithUser(t *testing.T) {
+	backend := &TestBackend{}
+	client := New(Configuration{Backend: backend, Sync: true})
+	client.SetUser(User{ID: "1"})
+
+	client.Event("login", map[string]any{})
+	client.Configure(Configuration{EventsIncludeUser: true, Sync: true})
+	client.Event("login", map[string]any{})
+
+	events := backend.GetEvents()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events. actual=%d", len(events))
+	}
+	if _, ok := events[0].Data["user_id"]; ok {
+		t.Errorf("Expected user to be omitted by default. actual=%#v", events[0].Data)
+	}
+	if events[1].Data["user_id"] != "1" {
+		t.Errorf("Expected user in event data. actual=%#v", events[1].Data)
+	}
+}